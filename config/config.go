@@ -0,0 +1,261 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+
+	"github.com/f0rt/golangAnnotations/generator"
+	"gopkg.in/yaml.v3"
+)
+
+// Basename is the name of the configuration file; it is looked up with each of the Extensions
+const Basename = "golangAnnotations"
+
+// Extensions are tried in this order within each dir
+var Extensions = []string{".yaml", ".yml", ".json"}
+
+// Config is the project configuration of golangAnnotations. Everything that is not configured keeps its default.
+type Config struct {
+	// Prefix of every generated file: files with this prefix are never parsed as input
+	Prefix string `yaml:"prefix" json:"prefix"`
+
+	// Include and Exclude are regular expressions that select the golang sources to be parsed.
+	// An empty Exclude excludes the generated sources.
+	Include string `yaml:"include" json:"include"`
+	Exclude string `yaml:"exclude" json:"exclude"`
+
+	// Generators to run, in this order (default: all), and generators to skip
+	Generators []string `yaml:"generators" json:"generators"`
+	Skip       []string `yaml:"skip" json:"skip"`
+
+	Output Output `yaml:"output" json:"output"`
+
+	// Imports maps package-names that are used in generated code onto their import-path
+	Imports map[string]string `yaml:"imports" json:"imports"`
+
+	// Options per generator-name: these are passed to plugins as well
+	Options map[string]map[string]string `yaml:"options" json:"options"`
+
+	// Prune removes the files that an earlier run generated but that are no longer generated
+	Prune bool `yaml:"prune" json:"prune"`
+
+	// Parallelism is the number of generators that run concurrently
+	Parallelism int `yaml:"parallelism" json:"parallelism"`
+}
+
+// Output determines the names of the packages that are generated next to an annotated package
+type Output struct {
+	StoreSuffix     string `yaml:"storeSuffix" json:"storeSuffix"`
+	PublisherSuffix string `yaml:"publisherSuffix" json:"publisherSuffix"`
+	TestLogSuffix   string `yaml:"testLogSuffix" json:"testLogSuffix"`
+}
+
+// RuntimeModule contains a reference implementation of every runtime package: generated code imports these by default
+const RuntimeModule = "github.com/f0rt/golangAnnotations/runtime"
+
+// RuntimePackages are the names of the packages that generated code depends on at runtime. A project configures
+// their import-paths with Imports, so that generated code compiles against its own packages.
+var RuntimePackages = []string{
+	"bus",
+	"ctx",
+	"datastore",
+	"devmode",
+	"environ",
+	"envelope",
+	"errorh",
+	"eventMetaData",
+	"eventStore",
+	"httpparser",
+	"idempotency",
+	"libtest",
+	"mydate",
+	"myerrorhandling",
+	"mylog",
+	"myqueue",
+	"mytime",
+	"myuuid",
+	"publisher",
+	"queue",
+	"request",
+	"store",
+}
+
+// IsRuntimePackage tells if generated code may refer to the package with the given name
+func IsRuntimePackage(name string) bool {
+	for _, p := range RuntimePackages {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// OptionTimestampLocation is the expression of the *time.Location in which event timestamps are presented
+	OptionTimestampLocation = "timestampLocation"
+
+	// OptionRESTRouter selects the router on which generated http-handlers are registered: mux (gorilla/mux) or
+	// servemux (net/http)
+	OptionRESTRouter = "router"
+
+	// OptionOpenAPIFormats is a comma separated list of the formats of the OpenAPI documents: json and/or yaml
+	OptionOpenAPIFormats = "formats"
+	// OptionOpenAPIVersion is the version of the api that the OpenAPI documents describe
+	OptionOpenAPIVersion = "apiVersion"
+	// OptionOpenAPISecurityScheme is the security-scheme of the operations with roles, like http:bearer or
+	// apiKey:header:X-Api-Key; without it the roles are only listed
+	OptionOpenAPISecurityScheme = "securityScheme"
+)
+
+func Default() Config {
+	return Config{
+		Prefix:     generator.GenfilePrefix,
+		Include:    "^.*.go$",
+		Exclude:    "",
+		Generators: []string{},
+		Skip:       []string{},
+		Output: Output{
+			StoreSuffix:     "Store",
+			PublisherSuffix: "Publisher",
+			TestLogSuffix:   "TestLog",
+		},
+		Imports: defaultImports(),
+		Options: map[string]map[string]string{
+			"event": {
+				OptionTimestampLocation: "mytime.DutchLocation",
+			},
+			"rest": {
+				OptionRESTRouter: "mux",
+			},
+			"openapi": {
+				OptionOpenAPIFormats: "json",
+				OptionOpenAPIVersion: "1.0.0",
+			},
+		},
+		Prune:       true,
+		Parallelism: runtime.NumCPU(),
+	}
+}
+
+func defaultImports() map[string]string {
+	imports := map[string]string{}
+	for _, name := range RuntimePackages {
+		imports[name] = RuntimeModule + "/" + name
+	}
+	return imports
+}
+
+// Find looks for a configuration file in dir and its parents. An empty filename is returned when there is none.
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		for _, ext := range Extensions {
+			filename := filepath.Join(dir, Basename+ext)
+			if _, err := os.Stat(filename); err == nil {
+				return filename, nil
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// Load reads a configuration file (yaml or json) on top of the defaults
+func Load(filename string) (Config, error) {
+	cfg := Default()
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return cfg, fmt.Errorf("Error reading config %s: %w", filename, err)
+	}
+	if filepath.Ext(filename) == ".json" {
+		err = json.Unmarshal(data, &cfg)
+	} else {
+		err = yaml.Unmarshal(data, &cfg)
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("Error parsing config %s: %w", filename, err)
+	}
+
+	cfg.addDefaultOptions()
+
+	err = cfg.validate()
+	if err != nil {
+		return cfg, fmt.Errorf("Invalid config %s: %w", filename, err)
+	}
+	return cfg, nil
+}
+
+// addDefaultOptions keeps the default options of a generator when the file configures only some of them
+func (c *Config) addDefaultOptions() {
+	for generatorName, options := range Default().Options {
+		if c.Options == nil {
+			c.Options = map[string]map[string]string{}
+		}
+		if c.Options[generatorName] == nil {
+			c.Options[generatorName] = map[string]string{}
+		}
+		for key, value := range options {
+			if _, ok := c.Options[generatorName][key]; !ok {
+				c.Options[generatorName][key] = value
+			}
+		}
+	}
+}
+
+func (c Config) validate() error {
+	if c.Prefix == "" {
+		return fmt.Errorf("prefix must not be empty: generated files would be parsed as input")
+	}
+	include, exclude := c.SourcePatterns()
+	for _, pattern := range []string{include, exclude} {
+		_, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %s: %w", pattern, err)
+		}
+	}
+	suffixes := []struct{ name, value string }{
+		{"storeSuffix", c.Output.StoreSuffix},
+		{"publisherSuffix", c.Output.PublisherSuffix},
+		{"testLogSuffix", c.Output.TestLogSuffix},
+	}
+	for _, suffix := range suffixes {
+		if suffix.value == "" {
+			return fmt.Errorf("output.%s must not be empty: the generated package would have the name of the annotated one", suffix.name)
+		}
+	}
+	if c.Parallelism < 1 {
+		return fmt.Errorf("parallelism must be at least 1, got %d", c.Parallelism)
+	}
+	for name, importPath := range c.Imports {
+		if importPath == "" {
+			return fmt.Errorf("empty import-path for package %s", name)
+		}
+	}
+	return nil
+}
+
+// SourcePatterns returns the regular expressions that select the golang sources to be parsed
+func (c Config) SourcePatterns() (string, string) {
+	exclude := c.Exclude
+	if exclude == "" {
+		exclude = "^" + regexp.QuoteMeta(c.Prefix) + ".*.go$"
+	}
+	return c.Include, exclude
+}
+
+// Option returns the value of an option of a generator, or an empty string
+func (c Config) Option(generatorName string, key string) string {
+	return c.Options[generatorName][key]
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.RemoveAll("./testData")
+}
+
+func TestFindLooksUpwards(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	assert.NoError(t, os.MkdirAll("testData/project/pkg/sub", 0777))
+	assert.NoError(t, ioutil.WriteFile("testData/project/"+Basename+".json", []byte("{}"), 0644))
+
+	filename, err := Find("testData/project/pkg/sub")
+	assert.NoError(t, err)
+	expected, _ := filepath.Abs("testData/project/" + Basename + ".json")
+	assert.Equal(t, expected, filename)
+
+	// yaml is preferred within the same dir
+	assert.NoError(t, ioutil.WriteFile("testData/project/pkg/"+Basename+".yaml", []byte(""), 0644))
+	filename, err = Find("testData/project/pkg/sub")
+	assert.NoError(t, err)
+	expected, _ = filepath.Abs("testData/project/pkg/" + Basename + ".yaml")
+	assert.Equal(t, expected, filename)
+}
+
+func TestLoadYaml(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	assert.NoError(t, os.MkdirAll("testData", 0777))
+	filename := "testData/" + Basename + ".yaml"
+	assert.NoError(t, ioutil.WriteFile(filename, []byte(`
+prefix: zz_
+generators: [rest, event]
+output:
+  storeSuffix: Repo
+imports:
+  errorh: example.com/runtime/errorh
+options:
+  event:
+    other: value
+`), 0644))
+
+	cfg, err := Load(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, "zz_", cfg.Prefix)
+	assert.Equal(t, []string{"rest", "event"}, cfg.Generators)
+	assert.Equal(t, "Repo", cfg.Output.StoreSuffix)
+	assert.Equal(t, "TestLog", cfg.Output.TestLogSuffix)
+	assert.Equal(t, "example.com/runtime/errorh", cfg.Imports["errorh"])
+	assert.Equal(t, RuntimeModule+"/mylog", cfg.Imports["mylog"])
+	assert.Equal(t, "value", cfg.Option("event", "other"))
+	assert.Equal(t, "mytime.DutchLocation", cfg.Option("event", OptionTimestampLocation))
+
+	include, exclude := cfg.SourcePatterns()
+	assert.Equal(t, "^.*.go$", include)
+	assert.Equal(t, "^zz_.*.go$", exclude)
+}
+
+func TestLoadJson(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	assert.NoError(t, os.MkdirAll("testData", 0777))
+	filename := "testData/" + Basename + ".json"
+	assert.NoError(t, ioutil.WriteFile(filename, []byte(`{"skip": ["ast"], "options": {"event": {"timestampLocation": "time.UTC"}}}`), 0644))
+
+	cfg, err := Load(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, "gen_", cfg.Prefix)
+	assert.Equal(t, []string{"ast"}, cfg.Skip)
+	assert.Equal(t, "time.UTC", cfg.Option("event", OptionTimestampLocation))
+}
+
+func TestLoadInvalid(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	assert.NoError(t, os.MkdirAll("testData", 0777))
+	filename := "testData/" + Basename + ".yaml"
+
+	assert.NoError(t, ioutil.WriteFile(filename, []byte(`prefix: ""`), 0644))
+	_, err := Load(filename)
+	assert.Error(t, err)
+
+	assert.NoError(t, ioutil.WriteFile(filename, []byte(`include: "("`), 0644))
+	_, err = Load(filename)
+	assert.Error(t, err)
+
+	for _, suffix := range []string{"storeSuffix", "publisherSuffix", "testLogSuffix"} {
+		assert.NoError(t, ioutil.WriteFile(filename, []byte("output:\n  "+suffix+": \"\"\n"), 0644))
+		_, err = Load(filename)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "output."+suffix+" must not be empty")
+		}
+	}
+
+	assert.NoError(t, ioutil.WriteFile(filename, []byte(`parallelism: 0`), 0644))
+	_, err = Load(filename)
+	assert.Error(t, err)
+}
+
+func TestLoadPruneAndParallelism(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	assert.NoError(t, os.MkdirAll("testData", 0777))
+	filename := "testData/" + Basename + ".yaml"
+
+	assert.NoError(t, ioutil.WriteFile(filename, []byte(`prefix: gen_`), 0644))
+	cfg, err := Load(filename)
+	assert.NoError(t, err)
+	assert.True(t, cfg.Prune)
+	assert.Equal(t, Default().Parallelism, cfg.Parallelism)
+
+	assert.NoError(t, ioutil.WriteFile(filename, []byte("prune: false\nparallelism: 2\n"), 0644))
+	cfg, err = Load(filename)
+	assert.NoError(t, err)
+	assert.False(t, cfg.Prune)
+	assert.Equal(t, 2, cfg.Parallelism)
+}
@@ -1,10 +1,14 @@
+//go:build !ci
 // +build !ci
 
 package myrest
 
 import (
 	"context"
+	"net/http"
 	"time"
+
+	"github.com/f0rt/golangAnnotations/runtime/request"
 )
 
 //go:generate golangAnnotations -input-dir .
@@ -41,6 +45,15 @@ type EtappeResult struct {
 type TourService struct {
 }
 
+func NewRestTourService() *TourService {
+	return &TourService{}
+}
+
+// extractRequestContext is called by the generated http-handlers for every request
+func extractRequestContext(c context.Context, r *http.Request) request.Context {
+	return request.NewMinimalContext(c, r)
+}
+
 // @RestOperation( method = "GET", path = "/{year}", format = "JSON" )
 func (ts TourService) getTourOnUID(c context.Context, year int) (*Tour, error) {
 	return &Tour{
@@ -0,0 +1,37 @@
+//go:build !ci && examples
+// +build !ci,examples
+
+// The tests use the generated http-handlers: `make examples` generates these before it runs the tests with tag
+// examples, so that a missing or broken generation fails the tests instead of skipping them.
+
+package myrest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func handler() http.Handler {
+	return NewRestTourService().HTTPHandler()
+}
+
+func TestGetTourOnUID(t *testing.T) {
+	w := httptest.NewRecorder()
+	handler().ServeHTTP(w, httptest.NewRequest("GET", "/api/tour/2016", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var tour Tour
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&tour))
+	assert.Equal(t, 2016, tour.Year)
+}
+
+func TestGetTourOnUIDInvalidYear(t *testing.T) {
+	w := httptest.NewRecorder()
+	handler().ServeHTTP(w, httptest.NewRequest("GET", "/api/tour/abc", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -1,3 +1,4 @@
+//go:build !ci
 // +build !ci
 
 package structExample
@@ -5,22 +6,18 @@ package structExample
 import (
 	"fmt"
 	"time"
+
+	"github.com/f0rt/golangAnnotations/runtime/eventMetaData"
 )
 
 //go:generate golangAnnotations -input-dir .
 
-type Metadata struct {
-	UUID          string
-	Timestamp     time.Time
-	EventTypeName string
-}
-
 // @JsonStruct()
 // @Event( aggregate = "Tour")
 type TourCreated struct {
-	Year     int      `json:"year"`
-	Tags     []string `json:"tags"`
-	Metadata Metadata `json:"-"`
+	Year     int                    `json:"year"`
+	Tags     []string               `json:"tags"`
+	Metadata eventMetaData.Metadata `json:"-"`
 }
 
 func (t TourCreated) GetUID() string {
@@ -30,11 +27,11 @@ func (t TourCreated) GetUID() string {
 // @JsonStruct()
 // @Event(aggregate = "Tour")
 type CyclistCreated struct {
-	Year        int      `json:"year"`
-	CyclistUID  string   `json:"cyclistUid"`
-	CyclistName string   `json:"cyclistName"`
-	CyclistTeam string   `json:"cyclistTeam"`
-	Metadata    Metadata `json:"-"`
+	Year        int                    `json:"year"`
+	CyclistUID  string                 `json:"cyclistUid"`
+	CyclistName string                 `json:"cyclistName"`
+	CyclistTeam string                 `json:"cyclistTeam"`
+	Metadata    eventMetaData.Metadata `json:"-"`
 }
 
 func (t CyclistCreated) GetUID() string {
@@ -43,14 +40,14 @@ func (t CyclistCreated) GetUID() string {
 
 // @Event(aggregate = "Tour")
 type EtappeCreated struct {
-	Year                 int       `json:"year"`
-	EtappeUID            string    `json:"etappeUid"`
-	EtappeDate           time.Time `json:"etappeDate"`
-	EtappeStartLocation  string    `json:"etappeStartLocation"`
-	EtappeFinishLocation string    `json:"etappeFinishLocation"`
-	EtappeLength         int       `json:"etappeLength"`
-	EtappeKind           int       `json:"etappeKind"`
-	Metadata             Metadata  `json:"-"`
+	Year                 int                    `json:"year"`
+	EtappeUID            string                 `json:"etappeUid"`
+	EtappeDate           time.Time              `json:"etappeDate"`
+	EtappeStartLocation  string                 `json:"etappeStartLocation"`
+	EtappeFinishLocation string                 `json:"etappeFinishLocation"`
+	EtappeLength         int                    `json:"etappeLength"`
+	EtappeKind           int                    `json:"etappeKind"`
+	Metadata             eventMetaData.Metadata `json:"-"`
 }
 
 func (t EtappeCreated) GetUID() string {
@@ -59,13 +56,13 @@ func (t EtappeCreated) GetUID() string {
 
 // @Event(aggregate = "Tour")
 type EtappeResultsCreated struct {
-	Year                     int      `json:"year"`
-	EtappeUID                string   `json:"EtappeUid"`
-	BestDayCyclistIds        []string `json:"bestDayCyclistIds"`
-	BestAllrounderCyclistIds []string `json:"bestAllrounderCyclistIds"`
-	BestSprinterCyclistIds   []string `json:"bestSprinterCyclistIds"`
-	BestClimberCyclistIds    []string `json:"bestClimberCyclistIds"`
-	Metadata                 Metadata `json:"-"`
+	Year                     int                    `json:"year"`
+	EtappeUID                string                 `json:"EtappeUid"`
+	BestDayCyclistIds        []string               `json:"bestDayCyclistIds"`
+	BestAllrounderCyclistIds []string               `json:"bestAllrounderCyclistIds"`
+	BestSprinterCyclistIds   []string               `json:"bestSprinterCyclistIds"`
+	BestClimberCyclistIds    []string               `json:"bestClimberCyclistIds"`
+	Metadata                 eventMetaData.Metadata `json:"-"`
 }
 
 func (t EtappeResultsCreated) GetUID() string {
@@ -74,11 +71,11 @@ func (t EtappeResultsCreated) GetUID() string {
 
 // @Event(aggregate = "Gambler")
 type GamblerCreated struct {
-	GamblerUID       string   `json:"gamblerUid"`
-	GamblerName      string   `json:"gamblerName"`
-	GamblerEmail     string   `json:"gamblerEmail"`
-	GamblerImageIUrl string   `json:"gamblerImageIUrl"`
-	Metadata         Metadata `json:"-"`
+	GamblerUID       string                 `json:"gamblerUid"`
+	GamblerName      string                 `json:"gamblerName"`
+	GamblerEmail     string                 `json:"gamblerEmail"`
+	GamblerImageIUrl string                 `json:"gamblerImageIUrl"`
+	Metadata         eventMetaData.Metadata `json:"-"`
 }
 
 func (t GamblerCreated) GetUID() string {
@@ -87,10 +84,10 @@ func (t GamblerCreated) GetUID() string {
 
 // @Event(aggregate = "Gambler")
 type GamblerTeamCreated struct {
-	GamblerUID      string   `json:"gamblerUid"`
-	Year            int      `json:"year"`
-	GamblerCyclists []string `json:"gamblerCyclists"`
-	Metadata        Metadata `json:"-"`
+	GamblerUID      string                 `json:"gamblerUid"`
+	Year            int                    `json:"year"`
+	GamblerCyclists []string               `json:"gamblerCyclists"`
+	Metadata        eventMetaData.Metadata `json:"-"`
 }
 
 func (t GamblerTeamCreated) GetUID() string {
@@ -99,12 +96,12 @@ func (t GamblerTeamCreated) GetUID() string {
 
 // @Event(aggregate = "News")
 type NewsItemCreated struct {
-	Year              int      `json:"year"`
-	Message           string   `json:"message"`
-	Sender            string   `json:"sender"`
-	RelatedCyclistUID string   `json:"relatedCyclistUid"`
-	RelatedEtappeUID  string   `json:"relatedEtappeUid"`
-	Metadata          Metadata `json:"-"`
+	Year              int                    `json:"year"`
+	Message           string                 `json:"message"`
+	Sender            string                 `json:"sender"`
+	RelatedCyclistUID string                 `json:"relatedCyclistUid"`
+	RelatedEtappeUID  string                 `json:"relatedEtappeUid"`
+	Metadata          eventMetaData.Metadata `json:"-"`
 }
 
 func (t NewsItemCreated) GetUID() string {
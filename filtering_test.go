@@ -4,11 +4,13 @@ import (
 	"regexp"
 	"testing"
 
+	"github.com/f0rt/golangAnnotations/config"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestFilenameFiltering(t *testing.T) {
-	var excludePattern = regexp.MustCompile(excludeMatchPattern)
+	_, exclude := config.Default().SourcePatterns()
+	var excludePattern = regexp.MustCompile(exclude)
 	assert.False(t, excludePattern.MatchString("a.go"))
 	assert.False(t, excludePattern.MatchString("a.txt"))
 	assert.True(t, excludePattern.MatchString("gen_a.go"))
@@ -3,7 +3,6 @@ package ast
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"os"
 
 	"github.com/f0rt/golangAnnotations/generator"
@@ -27,16 +26,16 @@ func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
 	return eventAnnotation.Get()
 }
 
-func (eg *Generator) Generate(inputDir string, parsedSources model.ParsedSources) error {
+func (eg *Generator) Generate(inputDir string, parsedSources model.ParsedSources, output generator.Output) error {
 
 	marshalled, err := json.MarshalIndent(parsedSources, "", "\t")
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("Error marshalling json-ast: %w", err)
 	}
 
 	if eg.targetFilename != "" {
 		filenamePath := generationUtil.Prefixed(inputDir + "/" + eg.targetFilename)
-		err = ioutil.WriteFile(filenamePath, marshalled, 0644)
+		err = output.Write(inputDir, filenamePath, marshalled)
 		if err != nil {
 			return fmt.Errorf("Error writing json-ast to file:%s", err)
 		}
@@ -0,0 +1,263 @@
+package driver
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/f0rt/golangAnnotations/generator"
+	"github.com/f0rt/golangAnnotations/generator/generationUtil"
+	"github.com/f0rt/golangAnnotations/generator/registry"
+	"github.com/f0rt/golangAnnotations/model"
+	"github.com/f0rt/golangAnnotations/parser"
+)
+
+// RecursiveSuffix makes an input-dir include all packages below it, like "./..." does for the go tool
+const RecursiveSuffix = "/..."
+
+// Options determine which generators run, where their files go and what happens to the files of earlier runs
+type Options struct {
+	Available   []registry.Entry
+	Selection   registry.Selection
+	Prune       bool
+	Output      generator.Output
+	Parallelism int
+}
+
+// Errors collects the errors of all packages and generators, so they can be reported at once
+type Errors []error
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+type packageRun struct {
+	dir           string
+	parsedSources model.ParsedSources
+	jobs          []*job
+	errs          Errors
+}
+
+// job is a single generator that runs for a single package
+type job struct {
+	pkg      *packageRun
+	entry    registry.Entry
+	recorder *generationUtil.Recorder
+	err      error
+}
+
+// Run generates the code for every package-dir. Packages and generators run concurrently, with at most
+// options.Parallelism at the same time. A failing generator does not stop the others: all errors are returned as
+// Errors. Diagnostics and errors are always returned in the order of the dirs and generators.
+func Run(dirs []string, options Options) ([]string, error) {
+	packages := make([]*packageRun, 0, len(dirs))
+	for _, dir := range dirs {
+		packages = append(packages, &packageRun{dir: dir})
+	}
+
+	forEach(len(packages), options.Parallelism, func(idx int) {
+		packages[idx].prepare(options)
+	})
+
+	jobs := []*job{}
+	for _, p := range packages {
+		jobs = append(jobs, p.jobs...)
+	}
+	forEach(len(jobs), options.Parallelism, func(idx int) {
+		jobs[idx].run()
+	})
+
+	forEach(len(packages), options.Parallelism, func(idx int) {
+		packages[idx].finish(options)
+	})
+
+	diagnostics := []string{}
+	errs := Errors{}
+	for _, p := range packages {
+		for _, j := range p.jobs {
+			for _, d := range j.recorder.Diagnostics() {
+				diagnostics = append(diagnostics, fmt.Sprintf("%s: %s", p.dir, d))
+			}
+		}
+		errs = append(errs, p.errs...)
+	}
+	if len(errs) > 0 {
+		return diagnostics, errs
+	}
+	return diagnostics, nil
+}
+
+// forEach calls fn for every index from 0 to n, using at most the given number of workers
+func forEach(n int, workers int, fn func(idx int)) {
+	if workers < 1 {
+		workers = 1
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indexes {
+				fn(idx)
+			}
+		}()
+	}
+	for idx := 0; idx < n; idx++ {
+		indexes <- idx
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// prepare parses the package and determines the generators that will run for it
+func (p *packageRun) prepare(options Options) {
+	include, exclude := generationUtil.GetConfig().SourcePatterns()
+	parsedSources, err := parser.New().ParseSourceDir(p.dir, include, exclude)
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s: Error parsing golang sources: %w", p.dir, err))
+		return
+	}
+	p.parsedSources = parsedSources
+
+	generators, err := registry.Select(options.Available, options.Selection.Override(parsedSources.PackageDocLines))
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s: Error selecting generators: %w", p.dir, err))
+		return
+	}
+	sources, err := sourceFilenames(p.dir)
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s: Error listing golang sources: %w", p.dir, err))
+		return
+	}
+	// tests are left out, so that editing a test does not rewrite the generated files
+	consumed := generationUtil.ConsumedSources(sources)
+	hash, err := generationUtil.HashSources(p.dir, consumed)
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s: Error hashing golang sources: %w", p.dir, err))
+		return
+	}
+	for _, entry := range generators {
+		header := generationUtil.NewHeader(entry.Name, consumed, hash)
+		p.jobs = append(p.jobs, &job{
+			pkg:      p,
+			entry:    entry,
+			recorder: generationUtil.NewRecorder(options.Output, header),
+		})
+	}
+}
+
+func (j *job) run() {
+	err := j.entry.Generator.Generate(j.pkg.dir, j.pkg.parsedSources, j.recorder)
+	if err != nil {
+		j.err = fmt.Errorf("%s: Error running generator %s: %w", j.pkg.dir, j.entry.Name, err)
+	}
+}
+
+// finish updates the manifest of the package once all its generators have run
+func (p *packageRun) finish(options Options) {
+	if len(p.errs) > 0 {
+		return
+	}
+	generated := map[string][]string{}
+	for _, j := range p.jobs {
+		if j.err != nil {
+			p.errs = append(p.errs, j.err)
+		}
+		generated[j.entry.Name] = append(generated[j.entry.Name], j.recorder.Filenames()...)
+	}
+	if len(p.errs) > 0 {
+		// do not remove files that failing generators would otherwise have produced
+		return
+	}
+
+	err := generationUtil.UpdateManifest(options.Output, p.dir, generated, options.Prune)
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s: Error updating manifest: %w", p.dir, err))
+	}
+}
+
+// ExpandInputDir returns the package-dirs to be examined: an input-dir that ends with "/..." is replaced by all
+// dirs below it that contain hand-written golang sources.
+func ExpandInputDir(inputDir string) ([]string, error) {
+	if !strings.HasSuffix(inputDir, RecursiveSuffix) {
+		return []string{inputDir}, nil
+	}
+	root := strings.TrimSuffix(inputDir, RecursiveSuffix)
+	if root == "" {
+		root = "."
+	}
+
+	dirs := []string{}
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != root && isIgnoredDir(info.Name()) {
+			return filepath.SkipDir
+		}
+		hasSources, err := containsSources(path)
+		if err != nil {
+			return err
+		}
+		if hasSources {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error expanding %s: %w", inputDir, err)
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// isIgnoredDir skips the same dirs as the go tool does
+func isIgnoredDir(name string) bool {
+	return name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+func containsSources(dir string) (bool, error) {
+	sources, err := sourceFilenames(dir)
+	if err != nil {
+		return false, err
+	}
+	return len(sources) > 0, nil
+}
+
+// sourceFilenames returns the names of the hand-written golang sources in dir, in alphabetical order
+func sourceFilenames(dir string) ([]string, error) {
+	include, exclude := generationUtil.GetConfig().SourcePatterns()
+	includePattern, err := regexp.Compile(include)
+	if err != nil {
+		return nil, err
+	}
+	excludePattern, err := regexp.Compile(exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	sources := []string{}
+	for _, f := range files {
+		if !f.IsDir() && includePattern.MatchString(f.Name()) && !excludePattern.MatchString(f.Name()) {
+			sources = append(sources, f.Name())
+		}
+	}
+	return sources, nil
+}
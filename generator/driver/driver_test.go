@@ -0,0 +1,257 @@
+package driver
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/f0rt/golangAnnotations/generator"
+	"github.com/f0rt/golangAnnotations/generator/annotation"
+	"github.com/f0rt/golangAnnotations/generator/generationUtil"
+	"github.com/f0rt/golangAnnotations/generator/registry"
+	"github.com/f0rt/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.RemoveAll("./testData")
+}
+
+func writeSource(t *testing.T, filename string, content string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(filename), 0777))
+	assert.NoError(t, ioutil.WriteFile(filename, []byte(content), 0644))
+}
+
+type testGenerator struct {
+	fail       bool
+	diagnostic string
+	// name of the generated files, "test" by default; none are generated when it is "-"
+	filename    string
+	annotations []annotation.AnnotationDescriptor
+}
+
+func (g *testGenerator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return g.annotations
+}
+
+func (g *testGenerator) Generate(inputDir string, parsedSources model.ParsedSources, output generator.Output) error {
+	if g.diagnostic != "" {
+		output.(generator.Reporter).Report(g.diagnostic)
+	}
+	if g.fail {
+		return fmt.Errorf("failing on request")
+	}
+	filename := g.filename
+	if filename == "" {
+		filename = "test"
+	} else if filename == "-" {
+		return nil
+	}
+	err := output.Write(inputDir, filepath.Join(inputDir, "gen_"+filename+".go"), []byte("package test\n"))
+	if err != nil {
+		return err
+	}
+	return output.Write(inputDir, filepath.Join(inputDir, "gen_"+filename+".txt"), []byte("generated"))
+}
+
+func TestExpandInputDir(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	writeSource(t, "testData/a/a.go", "package a")
+	writeSource(t, "testData/a/b/b.go", "package b")
+	writeSource(t, "testData/a/onlyGenerated/gen_x.go", "package onlyGenerated")
+	writeSource(t, "testData/a/vendor/v/v.go", "package v")
+	writeSource(t, "testData/a/.hidden/h.go", "package h")
+	writeSource(t, "testData/a/noSources/readme.txt", "")
+
+	dirs, err := ExpandInputDir("testData/a/...")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"testData/a", "testData/a/b"}, dirs)
+
+	dirs, err = ExpandInputDir("testData/a")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"testData/a"}, dirs)
+}
+
+func TestRunCollectsAllErrors(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	writeSource(t, "testData/a/a.go", "package a")
+	writeSource(t, "testData/b/b.go", "package b")
+
+	options := Options{
+		Available: []registry.Entry{
+			{Name: "failing", Generator: &testGenerator{fail: true}},
+			{Name: "ok", Generator: &testGenerator{}},
+		},
+		Selection:   registry.NewSelection("", ""),
+		Prune:       true,
+		Output:      generationUtil.NewFileOutput(),
+		Parallelism: 4,
+	}
+	_, err := Run([]string{"testData/a", "testData/b"}, options)
+	assert.Error(t, err)
+	errs, ok := err.(Errors)
+	assert.True(t, ok)
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "testData/a: Error running generator failing: failing on request", errs[0].Error())
+	assert.Equal(t, "testData/b: Error running generator failing: failing on request", errs[1].Error())
+
+	// the other generators did run
+	_, err = os.Stat("testData/a/gen_test.txt")
+	assert.NoError(t, err)
+	_, err = os.Stat("testData/b/gen_test.txt")
+	assert.NoError(t, err)
+}
+
+func TestRunIsDeterministic(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	dirs := []string{}
+	for idx := 0; idx < 20; idx++ {
+		dir := fmt.Sprintf("testData/p%02d", idx)
+		writeSource(t, dir+"/p.go", "package p")
+		dirs = append(dirs, dir)
+	}
+
+	options := Options{
+		Available: []registry.Entry{
+			{Name: "first", Generator: &testGenerator{diagnostic: "first"}},
+			{Name: "second", Generator: &testGenerator{diagnostic: "second", fail: true}},
+		},
+		Selection:   registry.NewSelection("", ""),
+		Output:      generationUtil.NewFileOutput(),
+		Parallelism: 8,
+	}
+	diagnostics, err := Run(dirs, options)
+	assert.Error(t, err)
+	assert.Len(t, err.(Errors), len(dirs))
+	assert.Len(t, diagnostics, 2*len(dirs))
+	for idx, dir := range dirs {
+		assert.Equal(t, dir+": first", diagnostics[2*idx])
+		assert.Equal(t, dir+": second", diagnostics[2*idx+1])
+		assert.Equal(t, dir+": Error running generator second: failing on request", err.(Errors)[idx].Error())
+	}
+}
+
+func TestRunWritesHeader(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	writeSource(t, "testData/a/b.go", "package a")
+	writeSource(t, "testData/a/a.go", "package a")
+
+	options := Options{
+		Available:   []registry.Entry{{Name: "ok", Generator: &testGenerator{}}},
+		Selection:   registry.NewSelection("", ""),
+		Output:      generationUtil.NewFileOutput(),
+		Parallelism: 1,
+	}
+	_, err := Run([]string{"testData/a"}, options)
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile("testData/a/gen_test.go")
+	assert.NoError(t, err)
+	header, ok := generationUtil.ParseHeader(data)
+	assert.True(t, ok)
+	assert.Equal(t, "ok", header.Generator)
+	assert.Equal(t, []string{"a.go", "b.go"}, header.Sources)
+	hash, err := generationUtil.HashSources("testData/a", []string{"a.go", "b.go"})
+	assert.NoError(t, err)
+	assert.Equal(t, hash, header.Hash)
+
+	// only go-files get a header
+	data, err = ioutil.ReadFile("testData/a/gen_test.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "generated", string(data))
+}
+
+func TestRunPrunesOnlyFilesOfSelectedGenerators(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	writeSource(t, "testData/a/a.go", "package a")
+
+	first := &testGenerator{filename: "first"}
+	options := Options{
+		Available: []registry.Entry{
+			{Name: "first", Generator: first},
+			{Name: "second", Generator: &testGenerator{filename: "second"}},
+		},
+		Selection:   registry.NewSelection("", ""),
+		Prune:       true,
+		Output:      generationUtil.NewFileOutput(),
+		Parallelism: 1,
+	}
+	_, err := Run([]string{"testData/a"}, options)
+	assert.NoError(t, err)
+
+	// running only the first generator keeps the files of the second
+	options.Selection = registry.NewSelection("first", "")
+	_, err = Run([]string{"testData/a"}, options)
+	assert.NoError(t, err)
+	for _, filename := range []string{"gen_first.go", "gen_first.txt", "gen_second.go", "gen_second.txt"} {
+		_, err = os.Stat(filepath.Join("testData/a", filename))
+		assert.NoError(t, err, filename)
+	}
+	data, err := ioutil.ReadFile(generationUtil.ManifestFilename("testData/a"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "gen_second.go\tsecond\n")
+	assert.Contains(t, string(data), "gen_second.txt\tsecond\n")
+
+	// the files that a selected generator no longer generates are removed
+	first.filename = "-"
+	_, err = Run([]string{"testData/a"}, options)
+	assert.NoError(t, err)
+	_, err = os.Stat("testData/a/gen_first.go")
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat("testData/a/gen_second.go")
+	assert.NoError(t, err)
+}
+
+func TestRunHashesAllSourcesButTests(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	writeSource(t, "testData/a/service.go", "package a\n\n// @Thing()\ntype Service struct{}\n\nfunc (s Service) create(p Person) error { return nil }\n")
+	writeSource(t, "testData/a/person.go", "package a\n\ntype Person struct{ Name string }\n")
+	writeSource(t, "testData/a/a_test.go", "package a\n")
+
+	options := Options{
+		Available: []registry.Entry{
+			{Name: "annotated", Generator: &testGenerator{filename: "annotated", annotations: []annotation.AnnotationDescriptor{{Name: "Thing"}}}},
+		},
+		Selection:   registry.NewSelection("", ""),
+		Output:      generationUtil.NewFileOutput(),
+		Parallelism: 1,
+	}
+	_, err := Run([]string{"testData/a"}, options)
+	assert.NoError(t, err)
+	data, err := ioutil.ReadFile("testData/a/gen_annotated.go")
+	assert.NoError(t, err)
+	before, _ := generationUtil.ParseHeader(data)
+	assert.Equal(t, []string{"person.go", "service.go"}, before.Sources)
+
+	// editing a test leaves the generated file as it is
+	writeSource(t, "testData/a/a_test.go", "package a // changed\n")
+	_, err = Run([]string{"testData/a"}, options)
+	assert.NoError(t, err)
+	data, err = ioutil.ReadFile("testData/a/gen_annotated.go")
+	assert.NoError(t, err)
+	header, _ := generationUtil.ParseHeader(data)
+	assert.Equal(t, before.Hash, header.Hash)
+
+	// the body-struct of an operation lives in another file than the annotation, but is read all the same
+	writeSource(t, "testData/a/person.go", "package a\n\ntype Person struct{ Name, Email string }\n")
+	_, err = Run([]string{"testData/a"}, options)
+	assert.NoError(t, err)
+	data, err = ioutil.ReadFile("testData/a/gen_annotated.go")
+	assert.NoError(t, err)
+	header, _ = generationUtil.ParseHeader(data)
+	assert.NotEqual(t, before.Hash, header.Hash)
+}
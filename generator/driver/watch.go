@@ -0,0 +1,133 @@
+package driver
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/f0rt/golangAnnotations/generator/generationUtil"
+)
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watcher polls the hand-written sources below an input-dir. Generated files are no sources, so the files that
+// a regeneration writes never trigger another one.
+type Watcher struct {
+	inputDir string
+	states   map[string]map[string]fileState
+}
+
+func NewWatcher(inputDir string) *Watcher {
+	return &Watcher{
+		inputDir: inputDir,
+		states:   map[string]map[string]fileState{},
+	}
+}
+
+// Changed returns the package-dirs of which a source has been added, modified or removed since the previous call.
+// The first call returns all package-dirs. A dir without sources is forgotten: there is nothing to generate for it.
+func (w *Watcher) Changed() ([]string, error) {
+	dirs, err := ExpandInputDir(w.inputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	states := map[string]map[string]fileState{}
+	changed := []string{}
+	for _, dir := range dirs {
+		state, err := sourceStates(dir)
+		if err != nil {
+			return nil, err
+		}
+		if len(state) == 0 {
+			continue
+		}
+		states[dir] = state
+		if !sameStates(w.states[dir], state) {
+			changed = append(changed, dir)
+		}
+	}
+	w.states = states
+	sort.Strings(changed)
+	return changed, nil
+}
+
+func sourceStates(dir string) (map[string]fileState, error) {
+	sources, err := sourceFilenames(dir)
+	if err != nil {
+		return nil, err
+	}
+	states := map[string]fileState{}
+	for _, source := range sources {
+		info, err := os.Stat(filepath.Join(dir, source))
+		if os.IsNotExist(err) {
+			continue // removed in the meantime
+		}
+		if err != nil {
+			return nil, err
+		}
+		states[source] = fileState{modTime: info.ModTime(), size: info.Size()}
+	}
+	return states, nil
+}
+
+func sameStates(previous map[string]fileState, current map[string]fileState) bool {
+	if previous == nil || len(previous) != len(current) {
+		return false
+	}
+	for source, state := range current {
+		p, ok := previous[source]
+		if !ok || !p.modTime.Equal(state.modTime) || p.size != state.size {
+			return false
+		}
+	}
+	return true
+}
+
+// Round describes a single (re)generation in watch-mode
+type Round struct {
+	Dirs        []string
+	Diagnostics []string
+	Err         error
+	Summary     string
+}
+
+// Watch generates the code of all packages below the input-dir and then regenerates the packages with changed
+// sources every interval, until stop is closed. Every round is reported as soon as it has finished; a failing
+// round does not stop watching.
+func Watch(inputDir string, options Options, interval time.Duration, stop <-chan struct{}, report func(Round)) {
+	watcher := NewWatcher(inputDir)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		dirs, err := watcher.Changed()
+		if err != nil {
+			report(Round{Err: err})
+		} else if len(dirs) > 0 {
+			report(regenerate(dirs, options))
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func regenerate(dirs []string, options Options) Round {
+	output := generationUtil.NewFileOutput()
+	options.Output = output
+	diagnostics, err := Run(dirs, options)
+	return Round{
+		Dirs:        dirs,
+		Diagnostics: diagnostics,
+		Err:         err,
+		Summary:     output.Summary(),
+	}
+}
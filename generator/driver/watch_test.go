@@ -0,0 +1,81 @@
+package driver
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/f0rt/golangAnnotations/generator/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatcherChanged(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	writeSource(t, "testData/a/a.go", "package a")
+	writeSource(t, "testData/b/b.go", "package b")
+
+	watcher := NewWatcher("testData/...")
+	changed, err := watcher.Changed()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"testData/a", "testData/b"}, changed)
+
+	changed, err = watcher.Changed()
+	assert.NoError(t, err)
+	assert.Empty(t, changed)
+
+	// generated files are ignored
+	writeSource(t, "testData/a/gen_a.go", "package a")
+	changed, err = watcher.Changed()
+	assert.NoError(t, err)
+	assert.Empty(t, changed)
+
+	writeSource(t, "testData/b/b.go", "package b // modified")
+	writeSource(t, "testData/c/c.go", "package c")
+	changed, err = watcher.Changed()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"testData/b", "testData/c"}, changed)
+
+	assert.NoError(t, os.Remove("testData/a/a.go"))
+	changed, err = watcher.Changed()
+	assert.NoError(t, err)
+	assert.Empty(t, changed)
+}
+
+func TestWatchRegeneratesChangedPackages(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	writeSource(t, "testData/a/a.go", "package a")
+	writeSource(t, "testData/b/b.go", "package b")
+
+	options := Options{
+		Available:   []registry.Entry{{Name: "ok", Generator: &testGenerator{}}},
+		Selection:   registry.NewSelection("", ""),
+		Parallelism: 2,
+	}
+	rounds := make(chan Round)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		Watch("testData/...", options, 10*time.Millisecond, stop, func(round Round) {
+			rounds <- round
+		})
+		close(done)
+	}()
+
+	round := <-rounds
+	assert.NoError(t, round.Err)
+	assert.Equal(t, []string{"testData/a", "testData/b"}, round.Dirs)
+	assert.Equal(t, "6 files created, 0 updated, 0 unchanged, 0 removed", round.Summary)
+
+	writeSource(t, "testData/b/b.go", "package b // modified")
+	round = <-rounds
+	assert.NoError(t, round.Err)
+	assert.Equal(t, []string{"testData/b"}, round.Dirs)
+	assert.Equal(t, "0 files created, 1 updated, 2 unchanged, 0 removed", round.Summary)
+
+	close(stop)
+	<-done
+}
@@ -1,14 +1,16 @@
 package event
 
-const aggregateTemplate = `// Generated automatically by golangAnnotations: do not edit manually
-
-package {{.PackageName}}
+const aggregateTemplate = `package {{.PackageName}}
 
+{{block "imports" .}}
 import (
 	"context"
 	"encoding/json"
 	"fmt"
+
+	{{RuntimeImports "envelope" "eventMetaData" "idempotency" "mylog" "request"}}
 )
+{{end}}
 
 const (
 {{range $aggr, $events := .AggregateMap -}}
@@ -1,8 +1,12 @@
 package event
 
-const anonymizedTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+const anonymizedTemplate = `package {{.PackageName}}
 
-package {{.PackageName}}
+{{block "imports" .}}
+import (
+	{{RuntimeImports "mydate"}}
+)
+{{end}}
 
 {{range .Structs -}}
 	{{if IsSensitiveEventOrEventPart . -}}
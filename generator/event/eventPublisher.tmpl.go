@@ -1,10 +1,16 @@
 package event
 
-const eventPublisherTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+const eventPublisherTemplate = `package {{PublisherPackageName .PackageName}}
 
-package {{.PackageName}}Publisher
+{{block "imports" .}}
+import (
+	"context"
 
-import "context"
+	{{.PackageImport}}
+
+	{{RuntimeImports "errorh" "publisher" "request"}}
+)
+{{end}}
 
 {{range .Structs -}}
 	{{if IsTransientEvent . -}}
@@ -1,14 +1,16 @@
 package event
 
-const eventStoreTemplate = `// Generated automatically by golangAnnotations: do not edit manually
-
-package {{.PackageName}}Store
+const eventStoreTemplate = `package {{StorePackageName .PackageName}}
 
+{{block "imports" .}}
 import (
 	"context"
 
-	"cloud.google.com/go/datastore"
+	{{.PackageImport}}
+
+	{{RuntimeImports "datastore" "errorh" "eventMetaData" "request" "store"}}
 )
+{{end}}
 
 {{range .Structs -}}
 
@@ -36,7 +38,7 @@ func StoreEvent{{.Name}}(c context.Context, rc request.Context, tx *datastore.Tr
 
 	evt.Metadata = eventMetaData.Metadata{
 		UUID:          envlp.UUID,
-		Timestamp:     envlp.Timestamp.In(mytime.DutchLocation),
+		Timestamp:     envlp.Timestamp.In({{TimestampLocation}}),
 		EventTypeName: envlp.EventTypeName,
 	}
 
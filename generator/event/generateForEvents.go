@@ -2,11 +2,11 @@ package event
 
 import (
 	"fmt"
-	"log"
 	"strings"
 	"text/template"
 	"unicode"
 
+	"github.com/f0rt/golangAnnotations/config"
 	"github.com/f0rt/golangAnnotations/generator"
 	"github.com/f0rt/golangAnnotations/generator/annotation"
 	"github.com/f0rt/golangAnnotations/generator/event/eventAnnotation"
@@ -34,6 +34,8 @@ type aggregateMap struct {
 type structures struct {
 	PackageName string
 	Structs     []model.Struct
+	// PackageImport imports the annotated package into a package next to it
+	PackageImport string
 }
 
 type Generator struct {
@@ -47,31 +49,45 @@ func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
 	return eventAnnotation.Get()
 }
 
-func (eg *Generator) Generate(inputDir string, parsedSource model.ParsedSources) error {
-	return generate(inputDir, parsedSource.Structs)
+func (eg *Generator) GetTemplates() map[string]string {
+	return map[string]string{
+		"aggregates":      aggregateTemplate,
+		"wrappers":        wrappersTemplate,
+		"anonymized":      anonymizedTemplate,
+		"event-store":     eventStoreTemplate,
+		"event-publisher": eventPublisherTemplate,
+		"wrappers-test":   wrappersTestTemplate,
+		"interface":       interfaceTemplate,
+	}
+}
+
+func (eg *Generator) Generate(inputDir string, parsedSource model.ParsedSources, output generator.Output) error {
+	return generate(inputDir, parsedSource.Structs, output)
 }
 
 type generateContext struct {
-	targetDir   string
+	target      generationUtil.Target
 	packageName string
 	structs     []model.Struct
+	output      generator.Output
 }
 
-func generate(inputDir string, structs []model.Struct) error {
+func generate(inputDir string, structs []model.Struct, output generator.Output) error {
 	packageName, err := generationUtil.GetPackageNameForStructs(structs)
 	if packageName == "" || err != nil {
 		return err
 	}
 
-	targetDir, err := generationUtil.DetermineTargetPath(inputDir, packageName)
+	target, err := generationUtil.DetermineTarget(inputDir, packageName)
 	if err != nil {
 		return err
 	}
 
 	ctx := generateContext{
-		targetDir:   targetDir,
+		target:      target,
 		packageName: packageName,
 		structs:     structs,
+		output:      output,
 	}
 
 	err = generateAggregates(ctx)
@@ -121,7 +137,7 @@ func generateAggregates(ctx generateContext) error {
 
 	err := generationUtil.Generate(generationUtil.Info{
 		Src:            ctx.packageName,
-		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/aggregates.go", ctx.targetDir)),
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/aggregates.go", ctx.target.Dir)),
 		TemplateName:   "aggregates",
 		TemplateString: aggregateTemplate,
 		FuncMap:        customTemplateFuncs,
@@ -129,10 +145,10 @@ func generateAggregates(ctx generateContext) error {
 			PackageName:  ctx.packageName,
 			AggregateMap: aggregates,
 		},
+		Output: ctx.output,
 	})
 	if err != nil {
-		log.Fatalf("Error generating aggregates (%s)", err)
-		return err
+		return fmt.Errorf("Error generating aggregates in package %s: %w", ctx.packageName, err)
 	}
 	return nil
 }
@@ -177,7 +193,7 @@ func generateWrappers(ctx generateContext) error {
 
 	err := generationUtil.Generate(generationUtil.Info{
 		Src:            ctx.packageName,
-		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/wrappers.go", ctx.targetDir)),
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/wrappers.go", ctx.target.Dir)),
 		TemplateName:   "wrappers",
 		TemplateString: wrappersTemplate,
 		FuncMap:        customTemplateFuncs,
@@ -185,10 +201,10 @@ func generateWrappers(ctx generateContext) error {
 			PackageName: ctx.packageName,
 			Structs:     ctx.structs,
 		},
+		Output: ctx.output,
 	})
 	if err != nil {
-		log.Fatalf("Error generating wrappers for structures (%s)", err)
-		return err
+		return fmt.Errorf("Error generating wrappers in package %s: %w", ctx.packageName, err)
 	}
 	return nil
 }
@@ -201,7 +217,7 @@ func generateAnonymized(ctx generateContext) error {
 
 	err := generationUtil.Generate(generationUtil.Info{
 		Src:            ctx.packageName,
-		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/anonymized.go", ctx.targetDir)),
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/anonymized.go", ctx.target.Dir)),
 		TemplateName:   "anonymized",
 		TemplateString: anonymizedTemplate,
 		FuncMap:        customTemplateFuncs,
@@ -209,14 +225,29 @@ func generateAnonymized(ctx generateContext) error {
 			PackageName: ctx.packageName,
 			Structs:     ctx.structs,
 		},
+		Output: ctx.output,
 	})
 	if err != nil {
-		log.Fatalf("Error generating anonymized for structures (%s)", err)
-		return err
+		return fmt.Errorf("Error generating anonymized in package %s: %w", ctx.packageName, err)
 	}
 	return nil
 }
 
+// StorePackageName returns the name of the package with the generated event-store
+func StorePackageName(packageName string) string {
+	return packageName + generationUtil.GetConfig().Output.StoreSuffix
+}
+
+// PublisherPackageName returns the name of the package with the generated event-publisher
+func PublisherPackageName(packageName string) string {
+	return packageName + generationUtil.GetConfig().Output.PublisherSuffix
+}
+
+// TimestampLocation returns the expression of the location in which the timestamp of an event is presented
+func TimestampLocation() string {
+	return generationUtil.GetConfig().Option("event", config.OptionTimestampLocation)
+}
+
 func containsAny(structs []model.Struct, predicate func(_ model.Struct) bool) bool {
 	for _, s := range structs {
 		if predicate(s) {
@@ -232,20 +263,26 @@ func generateEventStore(ctx generateContext) error {
 		return nil
 	}
 
-	err := generationUtil.Generate(generationUtil.Info{
+	sibling, err := ctx.target.Sibling(generationUtil.GetConfig().Output.StoreSuffix)
+	if err != nil {
+		return err
+	}
+
+	err = generationUtil.Generate(generationUtil.Info{
 		Src:            ctx.packageName,
-		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/../%sStore/%sStore.go", ctx.targetDir, ctx.packageName, ctx.packageName)),
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/%s.go", sibling.Dir, sibling.PackageName)),
 		TemplateName:   "event-store",
 		TemplateString: eventStoreTemplate,
 		FuncMap:        customTemplateFuncs,
 		Data: structures{
-			PackageName: ctx.packageName,
-			Structs:     ctx.structs,
+			PackageName:   ctx.packageName,
+			Structs:       ctx.structs,
+			PackageImport: ctx.target.ImportSpec(),
 		},
+		Output: ctx.output,
 	})
 	if err != nil {
-		log.Fatalf("Error generating event-store for structures (%s)", err)
-		return err
+		return fmt.Errorf("Error generating event-store in package %s: %w", ctx.packageName, err)
 	}
 	return nil
 }
@@ -256,20 +293,26 @@ func generateEventPublisher(ctx generateContext) error {
 		return nil
 	}
 
-	err := generationUtil.Generate(generationUtil.Info{
+	sibling, err := ctx.target.Sibling(generationUtil.GetConfig().Output.PublisherSuffix)
+	if err != nil {
+		return err
+	}
+
+	err = generationUtil.Generate(generationUtil.Info{
 		Src:            ctx.packageName,
-		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/../%sPublisher/%sPublisher.go", ctx.targetDir, ctx.packageName, ctx.packageName)),
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/%s.go", sibling.Dir, sibling.PackageName)),
 		TemplateName:   "event-publisher",
 		TemplateString: eventPublisherTemplate,
 		FuncMap:        customTemplateFuncs,
 		Data: structures{
-			PackageName: ctx.packageName,
-			Structs:     ctx.structs,
+			PackageName:   ctx.packageName,
+			Structs:       ctx.structs,
+			PackageImport: ctx.target.ImportSpec(),
 		},
+		Output: ctx.output,
 	})
 	if err != nil {
-		log.Fatalf("Error generating event-publisher for structures (%s)", err)
-		return err
+		return fmt.Errorf("Error generating event-publisher in package %s: %w", ctx.packageName, err)
 	}
 	return nil
 }
@@ -282,7 +325,7 @@ func generateWrappersTest(ctx generateContext) error {
 
 	err := generationUtil.Generate(generationUtil.Info{
 		Src:            ctx.packageName,
-		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/wrappers_test.go", ctx.targetDir)),
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/wrappers_test.go", ctx.target.Dir)),
 		TemplateName:   "wrappers-test",
 		TemplateString: wrappersTestTemplate,
 		FuncMap:        customTemplateFuncs,
@@ -290,10 +333,10 @@ func generateWrappersTest(ctx generateContext) error {
 			PackageName: ctx.packageName,
 			Structs:     ctx.structs,
 		},
+		Output: ctx.output,
 	})
 	if err != nil {
-		log.Fatalf("Error generating wrappers-test for structures (%s)", err)
-		return err
+		return fmt.Errorf("Error generating wrappers-test in package %s: %w", ctx.packageName, err)
 	}
 	return nil
 }
@@ -306,7 +349,7 @@ func generateHandlerInterface(ctx generateContext) error {
 
 	err := generationUtil.Generate(generationUtil.Info{
 		Src:            ctx.packageName,
-		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/interface.go", ctx.targetDir)),
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/interface.go", ctx.target.Dir)),
 		TemplateName:   "interface",
 		TemplateString: interfaceTemplate,
 		FuncMap:        customTemplateFuncs,
@@ -314,15 +357,18 @@ func generateHandlerInterface(ctx generateContext) error {
 			PackageName: ctx.packageName,
 			Structs:     ctx.structs,
 		},
+		Output: ctx.output,
 	})
 	if err != nil {
-		log.Fatalf("Error generating interface for event-handlers (%s)", err)
-		return err
+		return fmt.Errorf("Error generating interface in package %s: %w", ctx.packageName, err)
 	}
 	return nil
 }
 
 var customTemplateFuncs = template.FuncMap{
+	"StorePackageName":            StorePackageName,
+	"PublisherPackageName":        PublisherPackageName,
+	"TimestampLocation":           TimestampLocation,
 	"GetEvents":                   GetEvents,
 	"IsEvent":                     IsEvent,
 	"IsRootEvent":                 IsRootEvent,
@@ -35,7 +35,7 @@ func TestGenerateForEvents(t *testing.T) {
 			},
 		},
 	}
-	err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s})
+	err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s}, generationUtil.NewFileOutput())
 	assert.Nil(t, err)
 
 	// check that generated files exisst
@@ -1,10 +1,14 @@
 package event
 
-const interfaceTemplate = `// Generated automatically by golangAnnotations: do not edit manually
+const interfaceTemplate = `package {{.PackageName}}
 
-package {{.PackageName}}
+{{block "imports" .}}
+import (
+	"context"
 
-import "context"
+	{{RuntimeImports "request"}}
+)
+{{end}}
 
 {{$packageName := .PackageName}}
 
@@ -1,14 +1,16 @@
 package event
 
-const wrappersTemplate = `// Generated automatically by golangAnnotations: do not edit manually
-
-package {{.PackageName}}
+const wrappersTemplate = `package {{.PackageName}}
 
+{{block "imports" .}}
 import (
 	"encoding/json"
 	"fmt"
 	"log"
+
+	{{RuntimeImports "envelope" "eventMetaData" "mytime" "myuuid" "request"}}
 )
+{{end}}
 
 const (
 {{range .Structs -}}
@@ -97,7 +99,7 @@ func UnWrap{{.Name}}(envlp *envelope.Envelope) (*{{.Name}}, error) {
 	evt.Metadata = eventMetaData.Metadata{
 		UUID:          envlp.UUID,
 		AdminUserUID:  envlp.AdminUserUID,
-		Timestamp:     envlp.Timestamp.In(mytime.DutchLocation),
+		Timestamp:     envlp.Timestamp.In({{TimestampLocation}}),
 		EventTypeName: envlp.EventTypeName,
 	}
 
@@ -1,16 +1,18 @@
 package event
 
-const wrappersTestTemplate = `// Generated automatically by golangAnnotations: do not edit manually
-
-package {{.PackageName}}
+const wrappersTestTemplate = `package {{.PackageName}}
 
+{{block "imports" .}}
 import (
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	{{RuntimeImports "mytime" "myuuid" "request"}}
 )
+{{end}}
 
 {{range .Structs -}}
 	{{if IsEvent . -}}
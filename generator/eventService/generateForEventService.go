@@ -2,7 +2,6 @@ package eventService
 
 import (
 	"fmt"
-	"log"
 	"sort"
 	"strings"
 	"text/template"
@@ -26,8 +25,15 @@ func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
 	return eventServiceAnnotation.Get()
 }
 
-func (eg *Generator) Generate(inputDir string, parsedSource model.ParsedSources) error {
-	return generate(inputDir, parsedSource.Structs)
+func (eg *Generator) GetTemplates() map[string]string {
+	return map[string]string{
+		"event-handlers": handlersTemplate,
+		"test-handlers":  testHandlersTemplate,
+	}
+}
+
+func (eg *Generator) Generate(inputDir string, parsedSource model.ParsedSources, output generator.Output) error {
+	return generate(inputDir, parsedSource.Structs, output)
 }
 
 type templateData struct {
@@ -35,7 +41,7 @@ type templateData struct {
 	Services    []model.Struct
 }
 
-func generate(inputDir string, structs []model.Struct) error {
+func generate(inputDir string, structs []model.Struct, output generator.Output) error {
 
 	packageName, err := generationUtil.GetPackageNameForStructs(structs)
 	if packageName == "" || err != nil {
@@ -61,10 +67,10 @@ func generate(inputDir string, structs []model.Struct) error {
 		PackageName: packageName,
 		Services:    eventServices,
 	}
-	return doGenerate(targetDir, packageName, data)
+	return doGenerate(targetDir, packageName, data, output)
 }
 
-func doGenerate(targetDir, packageName string, data templateData) error {
+func doGenerate(targetDir, packageName string, data templateData, output generator.Output) error {
 	err := generationUtil.Generate(generationUtil.Info{
 		Src:            packageName,
 		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/eventHandler.go", targetDir)),
@@ -72,10 +78,10 @@ func doGenerate(targetDir, packageName string, data templateData) error {
 		TemplateString: handlersTemplate,
 		FuncMap:        customTemplateFuncs,
 		Data:           data,
+		Output:         output,
 	})
 	if err != nil {
-		log.Fatalf("Error generating handlers for event-services in package %s: %s", packageName, err)
-		return err
+		return fmt.Errorf("Error generating handlers for event-services in package %s: %w", packageName, err)
 	}
 
 	for _, eventService := range data.Services {
@@ -87,10 +93,10 @@ func doGenerate(targetDir, packageName string, data templateData) error {
 				TemplateString: testHandlersTemplate,
 				FuncMap:        customTemplateFuncs,
 				Data:           data,
+				Output:         output,
 			})
 			if err != nil {
-				log.Fatalf("Error generating test-handlers for event-services in package %s: %s", packageName, err)
-				return err
+				return fmt.Errorf("Error generating test-handlers for event-services in package %s: %w", packageName, err)
 			}
 			break
 		}
@@ -1,9 +1,8 @@
 package eventService
 
-const handlersTemplate = `// Generated automatically by golangAnnotations: do not edit manually
-
-package {{.PackageName}}
+const handlersTemplate = `package {{.PackageName}}
 
+{{block "imports" .}}
 import (
 	"context"
 	"encoding/json"
@@ -12,7 +11,10 @@ import (
 	"strconv"
 
 	"github.com/gorilla/mux"
+
+	{{RuntimeImports "bus" "ctx" "devmode" "envelope" "environ" "errorh" "myerrorhandling" "mylog" "myqueue" "queue" "request"}}
 )
+{{end}}
 
 {{range $idxService, $service := .Services -}}
 
@@ -1,14 +1,16 @@
 package eventService
 
-const testHandlersTemplate = `// Generated automatically by golangAnnotations: do not edit manually
-
-package {{.PackageName}}
+const testHandlersTemplate = `package {{.PackageName}}
 
+{{block "imports" .}}
 import (
 	"context"
 	"fmt"
 	"testing"
+
+	{{RuntimeImports "envelope" "eventStore" "request" "store"}}
 )
+{{end}}
 
 {{range $idxService, $service := .Services -}}
 
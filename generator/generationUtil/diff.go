@@ -0,0 +1,266 @@
+package generationUtil
+
+import (
+	"fmt"
+	"strings"
+)
+
+const diffContextLines = 3
+
+type editKind int
+
+const (
+	editEqual editKind = iota
+	editDelete
+	editInsert
+)
+
+type edit struct {
+	kind    editKind
+	oldLine int
+	newLine int
+	text    string
+}
+
+// UnifiedDiff describes the changes from old to new content of a file in unified diff format
+func UnifiedDiff(filename string, old []byte, new []byte) string {
+	edits := diffLines(splitLines(string(old)), splitLines(string(new)))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- a/%s\n", filename)
+	fmt.Fprintf(&sb, "+++ b/%s\n", filename)
+	for _, h := range hunks(edits) {
+		writeHunk(&sb, h)
+	}
+	return sb.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines computes the shortest edit script with the linear space variant of the algorithm of Myers: the middle
+// snake of the shortest path splits the problem in two halves, that are solved recursively
+func diffLines(a []string, b []string) []edit {
+	d := differ{a: a, b: b, edits: make([]edit, 0, len(a)+len(b))}
+	d.diff(0, len(a), 0, len(b))
+	return deletesFirst(d.edits)
+}
+
+// deletesFirst puts the deleted lines of every change before the inserted ones, as diff tools do
+func deletesFirst(edits []edit) []edit {
+	result := make([]edit, 0, len(edits))
+	for i := 0; i < len(edits); {
+		if edits[i].kind == editEqual {
+			result = append(result, edits[i])
+			i++
+			continue
+		}
+		oldLine, newLine := edits[i].oldLine, edits[i].newLine
+		deletes, inserts := []edit{}, []edit{}
+		for ; i < len(edits) && edits[i].kind != editEqual; i++ {
+			if edits[i].kind == editDelete {
+				deletes = append(deletes, edits[i])
+			} else {
+				inserts = append(inserts, edits[i])
+			}
+		}
+		for _, e := range deletes {
+			e.newLine = newLine
+			result = append(result, e)
+		}
+		for _, e := range inserts {
+			e.oldLine = oldLine + len(deletes)
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+type differ struct {
+	a     []string
+	b     []string
+	edits []edit
+}
+
+// diff appends the edits that turn a[aLo:aHi] into b[bLo:bHi]
+func (d *differ) diff(aLo int, aHi int, bLo int, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.edits = append(d.edits, edit{kind: editEqual, oldLine: aLo, newLine: bLo, text: d.a[aLo]})
+		aLo++
+		bLo++
+	}
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && d.a[aHi-suffix-1] == d.b[bHi-suffix-1] {
+		suffix++
+	}
+	aHi, bHi = aHi-suffix, bHi-suffix
+
+	if x, y, ok := d.middleSnake(aLo, aHi, bLo, bHi); ok {
+		d.diff(aLo, x, bLo, y)
+		d.diff(x, aHi, y, bHi)
+	} else {
+		for x := aLo; x < aHi; x++ {
+			d.edits = append(d.edits, edit{kind: editDelete, oldLine: x, newLine: bLo, text: d.a[x]})
+		}
+		for y := bLo; y < bHi; y++ {
+			d.edits = append(d.edits, edit{kind: editInsert, oldLine: aHi, newLine: y, text: d.b[y]})
+		}
+	}
+
+	for i := 0; i < suffix; i++ {
+		d.edits = append(d.edits, edit{kind: editEqual, oldLine: aHi + i, newLine: bHi + i, text: d.a[aHi+i]})
+	}
+}
+
+// middleSnake searches the shortest path from both ends at once, and returns the point where they meet. False is
+// returned when the ranges cannot be split, because one of them is empty or the path is a single replacement.
+func (d *differ) middleSnake(aLo int, aHi int, bLo int, bHi int) (int, int, bool) {
+	a, b := d.a[aLo:aHi], d.b[bLo:bHi]
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return 0, 0, false
+	}
+	maxD := (n + m + 1) / 2
+	offset := maxD + 1
+	forward := make([]int, 2*offset+1)
+	backward := make([]int, 2*offset+1)
+	for i := range forward {
+		forward[i], backward[i] = -1, -1
+	}
+	forward[offset+1], backward[offset+1] = 0, 0
+
+	delta := n - m
+	// with an odd delta the forward path is the first to reach the backward one
+	odd := delta%2 != 0
+	// the diagonals that have run off the edges of the grid are no longer followed
+	fStart, fEnd, bStart, bEnd := 0, 0, 0, 0
+	for step := 0; step < maxD; step++ {
+		for k := -step + fStart; k <= step-fEnd; k += 2 {
+			var x int
+			if k == -step || (k != step && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+			switch {
+			case x > n:
+				fEnd += 2
+			case y > m:
+				fStart += 2
+			case odd:
+				if idx := offset + delta - k; idx >= 0 && idx < len(backward) && backward[idx] != -1 && x >= n-backward[idx] {
+					return aLo + x, bLo + y, true
+				}
+			}
+		}
+		for k := -step + bStart; k <= step-bEnd; k += 2 {
+			var x int
+			if k == -step || (k != step && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[n-x-1] == b[m-y-1] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+			switch {
+			case x > n:
+				bEnd += 2
+			case y > m:
+				bStart += 2
+			case !odd:
+				if idx := offset + delta - k; idx >= 0 && idx < len(forward) && forward[idx] != -1 {
+					fx := forward[idx]
+					fy := fx - (idx - offset)
+					if fx >= n-x {
+						return aLo + fx, bLo + fy, true
+					}
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// hunks groups the changes with their surrounding context; changes that are close together share a hunk
+func hunks(edits []edit) [][]edit {
+	result := make([][]edit, 0)
+	start, end := -1, -1
+	for i, e := range edits {
+		if e.kind == editEqual {
+			continue
+		}
+		from := maxInt(i-diffContextLines, 0)
+		if start >= 0 && from > end {
+			result = append(result, edits[start:end])
+			start = -1
+		}
+		if start < 0 {
+			start = from
+		}
+		end = minInt(i+diffContextLines+1, len(edits))
+	}
+	if start >= 0 {
+		result = append(result, edits[start:end])
+	}
+	return result
+}
+
+func writeHunk(sb *strings.Builder, hunk []edit) {
+	oldStart, newStart := hunk[0].oldLine, hunk[0].newLine
+	oldCount, newCount := 0, 0
+	for _, e := range hunk {
+		if e.kind != editInsert {
+			oldCount++
+		}
+		if e.kind != editDelete {
+			newCount++
+		}
+	}
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+	for _, e := range hunk {
+		switch e.kind {
+		case editEqual:
+			fmt.Fprintf(sb, " %s\n", e.text)
+		case editDelete:
+			fmt.Fprintf(sb, "-%s\n", e.text)
+		case editInsert:
+			fmt.Fprintf(sb, "+%s\n", e.text)
+		}
+	}
+}
+
+func hunkRange(start int, count int) string {
+	if count == 0 {
+		// empty range refers to the line before it
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
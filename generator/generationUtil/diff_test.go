@@ -0,0 +1,119 @@
+package generationUtil
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiffIdentical(t *testing.T) {
+	assert.Equal(t, "--- a/x.go\n+++ b/x.go\n", UnifiedDiff("x.go", []byte("a\nb\n"), []byte("a\nb\n")))
+}
+
+func TestUnifiedDiffSeparateHunks(t *testing.T) {
+	old := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	new := "1\ntwo\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n"
+	expected := `--- a/x.go
++++ b/x.go
+@@ -1,5 +1,5 @@
+ 1
+-2
++two
+ 3
+ 4
+ 5
+@@ -10,3 +10,4 @@
+ 10
+ 11
+ 12
++13
+`
+	assert.Equal(t, expected, UnifiedDiff("x.go", []byte(old), []byte(new)))
+}
+
+func TestUnifiedDiffCloseChangesShareHunk(t *testing.T) {
+	old := "a\nb\nc\nd\ne\n"
+	new := "a\nB\nc\nD\ne\n"
+	expected := `--- a/x.go
++++ b/x.go
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+-d
++D
+ e
+`
+	assert.Equal(t, expected, UnifiedDiff("x.go", []byte(old), []byte(new)))
+}
+
+// lcsLength computes the length of the longest common subsequence, the lines that a shortest edit script keeps
+func lcsLength(a []string, b []string) int {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = maxInt(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+	return lengths[0][0]
+}
+
+func TestDiffLinesIsShortestEditScript(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+	randomLines := func() []string {
+		lines := make([]string, random.Intn(30))
+		for i := range lines {
+			lines[i] = string(rune('a' + random.Intn(4)))
+		}
+		return lines
+	}
+	for i := 0; i < 500; i++ {
+		a, b := randomLines(), randomLines()
+		edits := diffLines(a, b)
+
+		old, new, equal := []string{}, []string{}, 0
+		for i, e := range edits {
+			switch e.kind {
+			case editEqual:
+				assert.Equal(t, a[e.oldLine], b[e.newLine])
+				old, new, equal = append(old, e.text), append(new, e.text), equal+1
+			case editDelete:
+				assert.Equal(t, len(new), e.newLine)
+				assert.False(t, i > 0 && edits[i-1].kind == editInsert, "insert before delete")
+				old = append(old, e.text)
+			case editInsert:
+				assert.Equal(t, len(old), e.oldLine)
+				new = append(new, e.text)
+			}
+		}
+		assert.Equal(t, a, old)
+		assert.Equal(t, b, new)
+		assert.Equal(t, lcsLength(a, b), equal, "%v -> %v", a, b)
+	}
+}
+
+func TestDiffLinesOfLargeRewrite(t *testing.T) {
+	a, b := make([]string, 6000), make([]string, 6000)
+	for i := range a {
+		a[i] = fmt.Sprintf("old %d", i)
+		b[i] = fmt.Sprintf("new %d", i)
+	}
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	edits := diffLines(a, b)
+	runtime.ReadMemStats(&after)
+
+	assert.Len(t, edits, 12000)
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(10<<20))
+}
@@ -2,11 +2,16 @@ package generationUtil
 
 import (
 	"path"
-
-	"github.com/f0rt/golangAnnotations/generator"
+	"path/filepath"
+	"strings"
 )
 
 func Prefixed(filenamePath string) string {
 	dir, filename := path.Split(filenamePath)
-	return dir + generator.GenfilePrefix + filename
+	return dir + cfg.Prefix + filename
+}
+
+// IsGenerated tells if the file has the name of a generated file
+func IsGenerated(filename string) bool {
+	return strings.HasPrefix(filepath.Base(filename), cfg.Prefix)
 }
@@ -0,0 +1,192 @@
+package generationUtil
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// knownImports maps the package-names that generated code refers to onto their import-path
+var knownImports = map[string]string{
+	"bytes":    "bytes",
+	"context":  "context",
+	"errors":   "errors",
+	"fmt":      "fmt",
+	"http":     "net/http",
+	"httptest": "net/http/httptest",
+	"io":       "io",
+	"ioutil":   "io/ioutil",
+	"json":     "encoding/json",
+	"log":      "log",
+	"os":       "os",
+	"reflect":  "reflect",
+	"sort":     "sort",
+	"strconv":  "strconv",
+	"strings":  "strings",
+	"testing":  "testing",
+	"time":     "time",
+	"url":      "net/url",
+	"assert":   "github.com/stretchr/testify/assert",
+	"mux":      "github.com/gorilla/mux",
+}
+
+// FormatSource adds missing imports of known packages, removes unused imports and formats the code like gofmt
+func FormatSource(filename string, src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, invalidSourceError(filename, src, err)
+	}
+
+	fixed, err := format.Source(fixImports(fset, file, src))
+	if err != nil {
+		return nil, invalidSourceError(filename, src, err)
+	}
+	return fixed, nil
+}
+
+func fixImports(fset *token.FileSet, file *ast.File, src []byte) []byte {
+	referenced := referencedPackages(file)
+
+	imports := map[string]string{}
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		name := importName(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if name == "_" || name == "." || referenced[name] {
+			imports[importPath] = importAlias(spec)
+			delete(referenced, name)
+		}
+	}
+	for name := range referenced {
+		if importPath, ok := lookupImport(name); ok {
+			imports[importPath] = ""
+			if importName(importPath) != name {
+				imports[importPath] = name
+			}
+		}
+	}
+
+	// replace the existing import declarations with a single, complete one
+	start := fset.Position(file.Name.End()).Offset
+	end := start
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			break
+		}
+		if end == start {
+			start = fset.Position(gen.Pos()).Offset
+		}
+		end = fset.Position(gen.End()).Offset
+	}
+
+	var buf bytes.Buffer
+	buf.Write(src[:start])
+	if start == fset.Position(file.Name.End()).Offset {
+		buf.WriteString("\n\n")
+	}
+	writeImports(&buf, imports)
+	buf.Write(src[end:])
+	return buf.Bytes()
+}
+
+// lookupImport prefers the import-paths of the project configuration over the known ones
+func lookupImport(name string) (string, bool) {
+	if importPath, ok := cfg.Imports[name]; ok {
+		return importPath, true
+	}
+	importPath, ok := knownImports[name]
+	return importPath, ok
+}
+
+// referencedPackages returns the names of the qualifiers that do not resolve to anything declared in the file itself
+func referencedPackages(file *ast.File) map[string]bool {
+	referenced := map[string]bool{}
+	ast.Inspect(file, func(node ast.Node) bool {
+		sel, ok := node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if ident, ok := sel.X.(*ast.Ident); ok && ident.Obj == nil {
+			referenced[ident.Name] = true
+		}
+		return true
+	})
+	return referenced
+}
+
+var majorVersionRegex = regexp.MustCompile(`^v[0-9]+$`)
+
+func importName(importPath string) string {
+	name := path.Base(importPath)
+	if majorVersionRegex.MatchString(name) {
+		name = path.Base(path.Dir(importPath))
+	}
+	return name
+}
+
+func importAlias(spec *ast.ImportSpec) string {
+	if spec.Name == nil {
+		return ""
+	}
+	return spec.Name.Name
+}
+
+func writeImports(buf *bytes.Buffer, imports map[string]string) {
+	if len(imports) == 0 {
+		return
+	}
+	stdlib := []string{}
+	other := []string{}
+	for importPath := range imports {
+		if strings.Contains(strings.Split(importPath, "/")[0], ".") {
+			other = append(other, importPath)
+		} else {
+			stdlib = append(stdlib, importPath)
+		}
+	}
+	sort.Strings(stdlib)
+	sort.Strings(other)
+
+	buf.WriteString("import (\n")
+	for idx, group := range [][]string{stdlib, other} {
+		if idx > 0 && len(stdlib) > 0 && len(group) > 0 {
+			buf.WriteString("\n")
+		}
+		for _, importPath := range group {
+			if alias := imports[importPath]; alias != "" {
+				fmt.Fprintf(buf, "\t%s %q\n", alias, importPath)
+			} else {
+				fmt.Fprintf(buf, "\t%q\n", importPath)
+			}
+		}
+	}
+	buf.WriteString(")")
+}
+
+// invalidSourceError reports the generated line that breaks the code, so the template can be fixed
+func invalidSourceError(filename string, src []byte, err error) error {
+	list, ok := err.(scanner.ErrorList)
+	if !ok || len(list) == 0 {
+		return fmt.Errorf("Generated invalid code for %s: %s", filename, err)
+	}
+	first := list[0]
+	lines := strings.Split(string(src), "\n")
+	offending := ""
+	if first.Pos.Line > 0 && first.Pos.Line <= len(lines) {
+		offending = lines[first.Pos.Line-1]
+	}
+	return fmt.Errorf("Generated invalid code for %s: %s\n\t%d: %s", filename, first, first.Pos.Line, offending)
+}
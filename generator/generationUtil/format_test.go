@@ -0,0 +1,64 @@
+package generationUtil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatSourceFixesImports(t *testing.T) {
+	src := `// Generated automatically
+
+package example
+import (
+	"cloud.google.com/go/datastore"
+	myjson "encoding/json"
+)
+
+func doit(w http.ResponseWriter, id string) error {
+	fmt := "%s"
+	router := mux.NewRouter()
+	_ = router
+	_, err := myjson.Marshal(strings.ToUpper(id))
+	unknown.DoIt(fmt)
+	return err
+}
+`
+	expected := `// Generated automatically
+
+package example
+
+import (
+	myjson "encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+func doit(w http.ResponseWriter, id string) error {
+	fmt := "%s"
+	router := mux.NewRouter()
+	_ = router
+	_, err := myjson.Marshal(strings.ToUpper(id))
+	unknown.DoIt(fmt)
+	return err
+}
+`
+	formatted, err := FormatSource("example.go", []byte(src))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(formatted))
+}
+
+func TestFormatSourceWithoutImports(t *testing.T) {
+	formatted, err := FormatSource("example.go", []byte("package example\nfunc now() time.Time { return time.Now() }\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "package example\n\nimport (\n\t\"time\"\n)\n\nfunc now() time.Time { return time.Now() }\n", string(formatted))
+}
+
+func TestFormatSourceInvalidCode(t *testing.T) {
+	_, err := FormatSource("example.go", []byte("package example\n\nfunc doit() {\n\treturn }}\n}\n"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "example.go:4")
+	assert.Contains(t, err.Error(), "4: \treturn }}")
+}
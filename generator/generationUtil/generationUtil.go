@@ -1,13 +1,16 @@
 package generationUtil
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
 	"os"
-	"path"
 	"path/filepath"
 	"strings"
 	"text/template"
 
+	"github.com/f0rt/golangAnnotations/config"
+	"github.com/f0rt/golangAnnotations/generator"
 	"github.com/f0rt/golangAnnotations/model"
 )
 
@@ -64,29 +67,28 @@ func GetPackageNameForEnumsOrStructs(enums []model.Enum, structs []model.Struct)
 	return "", fmt.Errorf("List of enums and structs has multiple package-names")
 }
 
-func DetermineTargetPath(inputDir string, packageName string) (string, error) {
-	if inputDir == "" || packageName == "" {
-		return "", fmt.Errorf("Input params not set")
-	}
+var templateDir = ""
 
-	goPath := os.Getenv("GOPATH")
-	if goPath != "" {
-		// Perform some additional check when still using GOPATH
-		workDir, err := os.Getwd()
-		if err != nil {
-			return "", fmt.Errorf("Error getting working dir:%s", err)
-		}
+var cfg = config.Default()
 
-		if !strings.Contains(workDir, goPath) {
-			return "", fmt.Errorf("Code %s lives outside GOPATH:%s", workDir, goPath)
-		}
-	}
+// SetConfig sets the project configuration that applies to all generators
+func SetConfig(c config.Config) {
+	cfg = c
+}
 
-	baseDir := path.Base(inputDir)
-	if baseDir == "." || baseDir == packageName {
-		return inputDir, nil
-	}
-	return fmt.Sprintf("%s/%s", inputDir, packageName), nil
+// GetConfig returns the project configuration that applies to all generators
+func GetConfig() config.Config {
+	return cfg
+}
+
+// SetTemplateDir sets the directory where overrides of the built-in templates are looked up
+func SetTemplateDir(dir string) {
+	templateDir = dir
+}
+
+// TemplateOverrideFilename returns the filename that overrides the built-in template with the given name
+func TemplateOverrideFilename(dir string, templateName string) string {
+	return filepath.Join(dir, templateName+".tmpl")
 }
 
 type Info struct {
@@ -96,34 +98,53 @@ type Info struct {
 	TemplateString string
 	FuncMap        template.FuncMap
 	Data           interface{}
+	Output         generator.Output
 }
 
 func Generate(twd Info) error {
-	fmt.Fprintf(os.Stderr, "%s: Generated go file '%s' based on source '%s'\n", "golangAnnotations", twd.TargetFilename, twd.Src)
-
-	w, err := createFile(twd.TargetFilename)
+	t, err := parseTemplate(twd)
 	if err != nil {
 		return err
 	}
-	defer w.Close()
 
-	t := template.New(twd.TemplateName).Funcs(twd.FuncMap)
-	t, err = t.Parse(twd.TemplateString)
+	var buf bytes.Buffer
+	err = t.Execute(&buf, twd.Data)
 	if err != nil {
 		return err
 	}
 
-	return t.Execute(w, twd.Data)
+	content := buf.Bytes()
+	if strings.HasSuffix(twd.TargetFilename, ".go") {
+		content, err = FormatSource(twd.TargetFilename, content)
+		if err != nil {
+			return err
+		}
+	}
+
+	return twd.Output.Write(twd.Src, twd.TargetFilename, content)
 }
 
-func createFile(filename string) (*os.File, error) {
-	err := os.MkdirAll(filepath.Dir(filename), 0777)
+func parseTemplate(twd Info) (*template.Template, error) {
+	t, err := template.New(twd.TemplateName).Funcs(builtinFuncs).Funcs(twd.FuncMap).Parse(twd.TemplateString)
 	if err != nil {
 		return nil, err
 	}
-	w, err := os.Create(filename)
+	if templateDir == "" {
+		return t, nil
+	}
+
+	// An override replaces the whole template, or only the named blocks it (re)defines
+	overrideFilename := TemplateOverrideFilename(templateDir, twd.TemplateName)
+	override, err := ioutil.ReadFile(overrideFilename)
+	if os.IsNotExist(err) {
+		return t, nil
+	}
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("Error reading template override %s: %s", overrideFilename, err)
+	}
+	t, err = t.Parse(string(override))
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing template override %s: %s", overrideFilename, err)
 	}
-	return w, nil
+	return t, nil
 }
@@ -5,6 +5,7 @@ import (
 	"os"
 	"testing"
 	"text/template"
+	"time"
 
 	"github.com/f0rt/golangAnnotations/model"
 	"github.com/stretchr/testify/assert"
@@ -45,17 +46,50 @@ func TestDetermineTargetPathEmptyInput(t *testing.T) {
 }
 
 func TestDetermineTargetCurrent(t *testing.T) {
-	inputDir := "."
-	packageName := "generationUtil"
-	dir, _ := DetermineTargetPath(inputDir, packageName)
-	assert.Equal(t, ".", dir)
+	target, err := DetermineTarget(".", "generationUtil")
+	assert.NoError(t, err)
+	assert.Equal(t, ".", target.Dir)
+	assert.Equal(t, "github.com/f0rt/golangAnnotations/generator/generationUtil", target.ImportPath)
 }
 
-func TestDetermineTargetSubdir(t *testing.T) {
-	inputDir := "a/b"
-	packageName := "generationUtil"
-	dir, _ := DetermineTargetPath(inputDir, packageName)
-	assert.Equal(t, "a/b/generationUtil", dir)
+func TestDetermineTargetDirDiffersFromPackageName(t *testing.T) {
+	target, err := DetermineTarget("../event/testData", "event")
+	assert.NoError(t, err)
+	assert.Equal(t, "../event/testData", target.Dir)
+	assert.Equal(t, "github.com/f0rt/golangAnnotations/generator/event/testData", target.ImportPath)
+	assert.Equal(t, `event "github.com/f0rt/golangAnnotations/generator/event/testData"`, target.ImportSpec())
+
+	store, err := target.Sibling("Store")
+	assert.NoError(t, err)
+	assert.Equal(t, "../event/eventStore", store.Dir)
+	assert.Equal(t, "eventStore", store.PackageName)
+	assert.Equal(t, "github.com/f0rt/golangAnnotations/generator/event/eventStore", store.ImportPath)
+
+	testLog := target.Sub("TestLog")
+	assert.Equal(t, "../event/testData/eventTestLog", testLog.Dir)
+	assert.Equal(t, "github.com/f0rt/golangAnnotations/generator/event/testData/eventTestLog", testLog.ImportPath)
+}
+
+func TestDetermineTargetInOtherModule(t *testing.T) {
+	defer os.RemoveAll("./test")
+	assert.NoError(t, os.MkdirAll("test/mod/pkg", 0777))
+	assert.NoError(t, ioutil.WriteFile("test/mod/go.mod", []byte("// my module\nmodule \"example.com/mod\"\n\ngo 1.22\n"), 0644))
+
+	target, err := DetermineTarget("test/mod/pkg", "pkg")
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/mod/pkg", target.ImportPath)
+
+	publisher, err := target.Sibling("Publisher")
+	assert.NoError(t, err)
+	assert.Equal(t, "test/mod/pkgPublisher", publisher.Dir)
+	assert.Equal(t, "example.com/mod/pkgPublisher", publisher.ImportPath)
+
+	// the sibling of the root package would be outside the module
+	root, err := DetermineTarget("test/mod", "mod")
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/mod", root.ImportPath)
+	_, err = root.Sibling("Store")
+	assert.Error(t, err)
 }
 
 func CommentedPackageName(s model.Struct) string {
@@ -74,6 +108,7 @@ func TestGenerateFileFromTemplate(t *testing.T) {
 		TemplateString: "{{.PackageName}}\n{{CommentedPackageName .}}",
 		FuncMap:        fm,
 		Data:           model.Struct{PackageName: "testit"},
+		Output:         NewFileOutput(),
 	})
 	assert.Nil(t, err)
 
@@ -85,3 +120,129 @@ func TestGenerateFileFromTemplate(t *testing.T) {
 	os.Remove("./test/doit.txt")
 	os.Remove("./test")
 }
+
+func TestGenerateWithTemplateOverride(t *testing.T) {
+	defer SetTemplateDir("")
+	defer os.RemoveAll("./test")
+
+	err := os.MkdirAll("./test/templates", 0777)
+	assert.NoError(t, err)
+	SetTemplateDir("./test/templates")
+
+	info := Info{
+		Src:            "testsrc",
+		TargetFilename: "test/doit.txt",
+		TemplateName:   "testtemplate",
+		TemplateString: `{{block "name" .}}{{.PackageName}}{{end}}-{{block "comment" .}}{{CommentedPackageName .}}{{end}}`,
+		FuncMap:        template.FuncMap{"CommentedPackageName": CommentedPackageName},
+		Data:           model.Struct{PackageName: "testit"},
+		Output:         NewFileOutput(),
+	}
+
+	{
+		// no override present
+		err = Generate(info)
+		assert.NoError(t, err)
+		data, _ := ioutil.ReadFile("test/doit.txt")
+		assert.Equal(t, "testit-// commented testit", string(data))
+	}
+	{
+		// partial override: only redefine a single block, funcs remain available
+		err = ioutil.WriteFile(TemplateOverrideFilename("./test/templates", "testtemplate"), []byte(`{{define "comment"}}/* {{CommentedPackageName .}} */{{end}}`), 0644)
+		assert.NoError(t, err)
+		err = Generate(info)
+		assert.NoError(t, err)
+		data, _ := ioutil.ReadFile("test/doit.txt")
+		assert.Equal(t, "testit-/* // commented testit */", string(data))
+	}
+	{
+		// full override
+		err = ioutil.WriteFile(TemplateOverrideFilename("./test/templates", "testtemplate"), []byte(`overridden {{template "name" .}}`), 0644)
+		assert.NoError(t, err)
+		err = Generate(info)
+		assert.NoError(t, err)
+		data, _ := ioutil.ReadFile("test/doit.txt")
+		assert.Equal(t, "overridden testit", string(data))
+	}
+	{
+		// invalid override
+		err = ioutil.WriteFile(TemplateOverrideFilename("./test/templates", "testtemplate"), []byte(`{{if}}`), 0644)
+		assert.NoError(t, err)
+		err = Generate(info)
+		assert.Error(t, err)
+	}
+}
+
+func TestCheckerLeavesFilesUntouched(t *testing.T) {
+	defer os.RemoveAll("./test")
+
+	err := os.MkdirAll("./test", 0777)
+	assert.NoError(t, err)
+	err = ioutil.WriteFile("./test/upToDate.txt", []byte("same\n"), 0644)
+	assert.NoError(t, err)
+	err = ioutil.WriteFile("./test/outdated.txt", []byte("old\n"), 0644)
+	assert.NoError(t, err)
+
+	checker := NewChecker()
+
+	assert.NoError(t, checker.Write("testsrc", "./test/upToDate.txt", []byte("same\n")))
+	assert.NoError(t, checker.Write("testsrc", "./test/outdated.txt", []byte("new\n")))
+	assert.NoError(t, checker.Write("testsrc", "./test/missing.txt", []byte("new\n")))
+
+	differences := checker.Differences()
+	assert.Len(t, differences, 2)
+	assert.Equal(t, "./test/missing.txt", differences[0].Filename)
+	assert.Equal(t, "--- a/./test/missing.txt\n+++ b/./test/missing.txt\n@@ -0,0 +1,1 @@\n+new\n", differences[0].Diff)
+	assert.Equal(t, "./test/outdated.txt", differences[1].Filename)
+	assert.Equal(t, "--- a/./test/outdated.txt\n+++ b/./test/outdated.txt\n@@ -1,1 +1,1 @@\n-old\n+new\n", differences[1].Diff)
+
+	data, err := ioutil.ReadFile("./test/outdated.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "old\n", string(data))
+	_, err = os.Stat("./test/missing.txt")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGenerateInvalidGoLeavesNoFile(t *testing.T) {
+	defer os.RemoveAll("./test")
+
+	err := Generate(Info{
+		Src:            "testsrc",
+		TargetFilename: "test/broken.go",
+		TemplateName:   "testtemplate",
+		TemplateString: "package {{.PackageName}}\n\nfunc {{.PackageName}}( {\n}\n",
+		Data:           model.Struct{PackageName: "testit"},
+		Output:         NewFileOutput(),
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "func testit( {")
+
+	_, err = os.Stat("test/broken.go")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestFileOutputOnlyWritesChanges(t *testing.T) {
+	defer os.RemoveAll("./test")
+
+	fo := NewFileOutput()
+	assert.NoError(t, fo.Write("testsrc", "test/a.txt", []byte("a")))
+	assert.NoError(t, fo.Write("testsrc", "test/b.txt", []byte("b")))
+
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	assert.NoError(t, os.Chtimes("test/a.txt", past, past))
+
+	assert.NoError(t, fo.Write("testsrc", "test/a.txt", []byte("a")))
+	assert.NoError(t, fo.Write("testsrc", "test/b.txt", []byte("changed")))
+	assert.NoError(t, fo.Remove("test/b.txt"))
+
+	info, err := os.Stat("test/a.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, past, info.ModTime())
+
+	// no temporary files left behind
+	files, err := ioutil.ReadDir("test")
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	assert.Equal(t, "2 files created, 1 updated, 1 unchanged, 1 removed", fo.Summary())
+}
@@ -0,0 +1,146 @@
+package generationUtil
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// headerLine marks a go-file as generated, following the convention that gopls and linters recognise
+const headerLine = "// Code generated by golangAnnotations. DO NOT EDIT."
+
+// legacyHeaderLine started the files that were generated by earlier versions
+const legacyHeaderLine = "// Generated automatically by golangAnnotations"
+
+var version = "dev"
+
+// SetVersion sets the version of golangAnnotations that is recorded in the header of generated files
+func SetVersion(v string) {
+	version = v
+}
+
+// Header records the provenance of a generated go-file
+type Header struct {
+	Version   string
+	Generator string
+	Sources   []string
+	Hash      string
+}
+
+// NewHeader describes the files that the named generator produces from the sources with the given hash
+func NewHeader(generatorName string, sources []string, hash string) Header {
+	return Header{
+		Version:   version,
+		Generator: generatorName,
+		Sources:   sources,
+		Hash:      hash,
+	}
+}
+
+// ConsumedSources returns the sources that generators read: all hand-written sources of the package but the tests.
+// A generator reads more than the declarations that carry its annotations, like the structs of request-bodies, so
+// it depends on every source of the package.
+func ConsumedSources(sources []string) []string {
+	consumed := []string{}
+	for _, source := range sources {
+		if !strings.HasSuffix(source, "_test.go") {
+			consumed = append(consumed, source)
+		}
+	}
+	return consumed
+}
+
+// HashSources hashes the names and the contents of the sources in dir, so that a change of any input changes the hash
+func HashSources(dir string, sources []string) (string, error) {
+	sorted := append([]string{}, sources...)
+	sort.Strings(sorted)
+
+	h := sha256.New()
+	for _, source := range sorted {
+		content, err := ioutil.ReadFile(filepath.Join(dir, source))
+		if err != nil {
+			return "", fmt.Errorf("Error reading source %s: %s", source, err)
+		}
+		fmt.Fprintf(h, "%s\n%d\n", source, len(content))
+		h.Write(content)
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (h Header) Bytes() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, headerLine)
+	fmt.Fprintf(&buf, "// version: %s\n", h.Version)
+	fmt.Fprintf(&buf, "// generator: %s\n", h.Generator)
+	fmt.Fprintf(&buf, "// sources: %s\n", strings.Join(h.Sources, ", "))
+	fmt.Fprintf(&buf, "// hash: %s\n", h.Hash)
+	fmt.Fprintln(&buf)
+	return buf.Bytes()
+}
+
+// Prepend puts the header on top of the content of a go-file; content that already has a header is kept as is
+func (h Header) Prepend(content []byte) []byte {
+	if _, ok := ParseHeader(content); ok {
+		return content
+	}
+	return append(h.Bytes(), content...)
+}
+
+// ParseHeader returns the header of a generated go-file; false is returned when the content has no such header
+func ParseHeader(content []byte) (Header, bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	if !scanner.Scan() || scanner.Text() != headerLine {
+		return Header{}, false
+	}
+	h := Header{Sources: []string{}}
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "// ") {
+			break
+		}
+		key, value, found := strings.Cut(strings.TrimPrefix(line, "// "), ": ")
+		if !found {
+			continue
+		}
+		switch key {
+		case "version":
+			h.Version = value
+		case "generator":
+			h.Generator = value
+		case "sources":
+			if value != "" {
+				h.Sources = strings.Split(value, ", ")
+			}
+		case "hash":
+			h.Hash = value
+		}
+	}
+	return h, true
+}
+
+// IsToolOwned tells if an existing file was generated by golangAnnotations: a go-file must have a header,
+// any other file must have the name of a generated file.
+func IsToolOwned(filename string) (bool, error) {
+	if !strings.HasSuffix(filename, ".go") {
+		return IsGenerated(filename), nil
+	}
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return false, err
+	}
+	return isToolOwnedContent(content), nil
+}
+
+func isToolOwnedContent(content []byte) bool {
+	if bytes.HasPrefix(content, []byte(legacyHeaderLine)) {
+		return true
+	}
+	_, ok := ParseHeader(content)
+	return ok
+}
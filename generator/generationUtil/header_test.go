@@ -0,0 +1,93 @@
+package generationUtil
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaderRoundTrip(t *testing.T) {
+	header := Header{Version: "1.0", Generator: "rest", Sources: []string{"a.go", "b.go"}, Hash: "sha256:abc"}
+	content := header.Prepend([]byte("package a\n"))
+
+	assert.Equal(t, "// Code generated by golangAnnotations. DO NOT EDIT.\n"+
+		"// version: 1.0\n"+
+		"// generator: rest\n"+
+		"// sources: a.go, b.go\n"+
+		"// hash: sha256:abc\n"+
+		"\n"+
+		"package a\n", string(content))
+
+	parsed, ok := ParseHeader(content)
+	assert.True(t, ok)
+	assert.Equal(t, header, parsed)
+
+	// prepending twice does not duplicate the header
+	assert.Equal(t, content, header.Prepend(content))
+
+	_, ok = ParseHeader([]byte("// Package a is hand-written\npackage a\n"))
+	assert.False(t, ok)
+}
+
+func TestHashSources(t *testing.T) {
+	defer os.RemoveAll("./test")
+	assert.NoError(t, os.MkdirAll("test", 0777))
+	assert.NoError(t, ioutil.WriteFile("test/a.go", []byte("package a"), 0644))
+
+	hash, err := HashSources("test", []string{"a.go"})
+	assert.NoError(t, err)
+	same, err := HashSources("test", []string{"a.go"})
+	assert.NoError(t, err)
+	assert.Equal(t, hash, same)
+
+	assert.NoError(t, ioutil.WriteFile("test/a.go", []byte("package a // changed"), 0644))
+	changed, err := HashSources("test", []string{"a.go"})
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, changed)
+
+	_, err = HashSources("test", []string{"missing.go"})
+	assert.Error(t, err)
+}
+
+func TestConsumedSources(t *testing.T) {
+	assert.Equal(t, []string{"a.go", "b.go"}, ConsumedSources([]string{"a.go", "a_test.go", "b.go"}))
+	assert.Equal(t, []string{}, ConsumedSources([]string{"a_test.go"}))
+}
+
+func TestIsToolOwned(t *testing.T) {
+	defer os.RemoveAll("./test")
+	assert.NoError(t, os.MkdirAll("test", 0777))
+	assert.NoError(t, ioutil.WriteFile("test/gen_new.go", Header{Generator: "test"}.Prepend([]byte("package a")), 0644))
+	assert.NoError(t, ioutil.WriteFile("test/gen_legacy.go", []byte(legacyHeaderLine+": do not edit manually\n\npackage a"), 0644))
+	assert.NoError(t, ioutil.WriteFile("test/gen_handWritten.go", []byte("package a"), 0644))
+	assert.NoError(t, ioutil.WriteFile("test/gen_ast.json", []byte("{}"), 0644))
+
+	for filename, expected := range map[string]bool{
+		"test/gen_new.go":         true,
+		"test/gen_legacy.go":      true,
+		"test/gen_handWritten.go": false,
+		"test/gen_ast.json":       true,
+	} {
+		owned, err := IsToolOwned(filename)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, owned, filename)
+	}
+}
+
+func TestHandWrittenFilesAreNotOverwritten(t *testing.T) {
+	defer os.RemoveAll("./test")
+	assert.NoError(t, os.MkdirAll("test", 0777))
+	assert.NoError(t, ioutil.WriteFile("test/gen_a.go", []byte("package a"), 0644))
+
+	recorder := NewRecorder(NewFileOutput(), Header{Generator: "test"})
+	assert.Error(t, recorder.Write("testsrc", "test/gen_a.go", []byte("package a\n\nvar x = 1\n")))
+
+	recorder = NewRecorder(NewChecker(), Header{Generator: "test"})
+	assert.Error(t, recorder.Write("testsrc", "test/gen_a.go", []byte("package a\n\nvar x = 1\n")))
+
+	data, err := ioutil.ReadFile("test/gen_a.go")
+	assert.NoError(t, err)
+	assert.Equal(t, "package a", string(data))
+}
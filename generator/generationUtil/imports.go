@@ -0,0 +1,39 @@
+package generationUtil
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/f0rt/golangAnnotations/config"
+)
+
+// builtinFuncs are available in every template, including the overrides
+var builtinFuncs = template.FuncMap{
+	"RuntimeImports": RuntimeImports,
+}
+
+// RuntimeImports returns the import-specs of the given runtime packages, as configured in the imports of the project
+// configuration. A package whose import-path is not configured is left out; unused imports are removed afterwards.
+func RuntimeImports(names ...string) (string, error) {
+	specs := []string{}
+	for _, name := range names {
+		if !config.IsRuntimePackage(name) {
+			return "", fmt.Errorf("Unknown runtime package %s", name)
+		}
+		importPath, ok := cfg.Imports[name]
+		if !ok {
+			continue
+		}
+		specs = append(specs, importSpec(name, importPath))
+	}
+	return strings.Join(specs, "\n"), nil
+}
+
+// importSpec aliases the import when the name used in generated code differs from the name of the package
+func importSpec(name string, importPath string) string {
+	if importName(importPath) == name {
+		return fmt.Sprintf("%q", importPath)
+	}
+	return fmt.Sprintf("%s %q", name, importPath)
+}
@@ -0,0 +1,43 @@
+package generationUtil
+
+import (
+	"testing"
+
+	"github.com/f0rt/golangAnnotations/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func withImports(imports map[string]string) func() {
+	c := config.Default()
+	c.Imports = imports
+	SetConfig(c)
+	return func() {
+		SetConfig(config.Default())
+	}
+}
+
+func TestRuntimeImportsUsesConfiguredPaths(t *testing.T) {
+	defer withImports(map[string]string{
+		"errorh": "example.com/runtime/errorh",
+		"mylog":  "example.com/runtime/logging",
+	})()
+
+	specs, err := RuntimeImports("errorh", "mylog", "request")
+	assert.NoError(t, err)
+	assert.Equal(t, "\"example.com/runtime/errorh\"\nmylog \"example.com/runtime/logging\"", specs)
+}
+
+func TestRuntimeImportsUnknownPackage(t *testing.T) {
+	_, err := RuntimeImports("errorhandling")
+	assert.EqualError(t, err, "Unknown runtime package errorhandling")
+}
+
+func TestFormatSourceAliasesConfiguredImports(t *testing.T) {
+	defer withImports(map[string]string{
+		"mylog": "example.com/runtime/logging",
+	})()
+
+	formatted, err := FormatSource("example.go", []byte("package example\nfunc doit() { mylog.New().Info(\"done\") }\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "package example\n\nimport (\n\tmylog \"example.com/runtime/logging\"\n)\n\nfunc doit() { mylog.New().Info(\"done\") }\n", string(formatted))
+}
@@ -0,0 +1,184 @@
+package generationUtil
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/f0rt/golangAnnotations/generator"
+)
+
+const manifestHeader = "# Files generated by golangAnnotations: do not edit manually"
+
+// ManifestFilename returns the file that lists the files that were generated for the package in dir
+func ManifestFilename(dir string) string {
+	return Prefixed(filepath.Join(dir, "manifest.txt"))
+}
+
+// Recorder is the output of a single run of a generator: it remembers which files have been generated,
+// collects the diagnostics and puts the header on top of every generated go-file
+type Recorder struct {
+	generator.Output
+	header      Header
+	filenames   []string
+	diagnostics []string
+}
+
+func NewRecorder(o generator.Output, header Header) *Recorder {
+	return &Recorder{
+		Output:      o,
+		header:      header,
+		filenames:   []string{},
+		diagnostics: []string{},
+	}
+}
+
+func (r *Recorder) Write(src string, targetFilename string, content []byte) error {
+	r.filenames = append(r.filenames, targetFilename)
+	if strings.HasSuffix(targetFilename, ".go") {
+		content = r.header.Prepend(content)
+	}
+	return r.Output.Write(src, targetFilename, content)
+}
+
+func (r *Recorder) Report(diagnostic string) {
+	r.diagnostics = append(r.diagnostics, diagnostic)
+}
+
+// Filenames returns the files that have been generated so far
+func (r *Recorder) Filenames() []string {
+	return r.filenames
+}
+
+// Diagnostics returns the diagnostics that have been reported so far
+func (r *Recorder) Diagnostics() []string {
+	return r.diagnostics
+}
+
+// UpdateManifest records the generated files of the package in dir, per generator. The generators that ran are the
+// keys of generated, even the ones that generated nothing. Files that one of them generated earlier but no longer
+// generates are removed, unless prune is false: these are then kept in the manifest so a later run can still remove
+// them. The files of generators that did not run are kept, and so is a file that has been replaced by a hand-written
+// one.
+func UpdateManifest(output generator.Output, dir string, generated map[string][]string, prune bool) error {
+	previous, err := readManifest(ManifestFilename(dir))
+	if err != nil {
+		return err
+	}
+
+	current := map[string]string{}
+	for generatorName, filenames := range generated {
+		for _, filename := range filenames {
+			rel, err := filepath.Rel(dir, filename)
+			if err != nil {
+				return fmt.Errorf("Error determining path of %s relative to %s: %s", filename, dir, err)
+			}
+			current[filepath.ToSlash(rel)] = generatorName
+		}
+	}
+
+	for _, entry := range previous {
+		if _, ok := current[entry.Filename]; ok || !IsGenerated(entry.Filename) {
+			continue
+		}
+		stale := filepath.Join(dir, filepath.FromSlash(entry.Filename))
+		if _, err := os.Stat(stale); os.IsNotExist(err) {
+			continue
+		}
+		owned, err := IsToolOwned(stale)
+		if err != nil {
+			return fmt.Errorf("Error reading stale generated file %s: %s", stale, err)
+		}
+		if !owned {
+			continue
+		}
+		if entry.Generator == "" {
+			// recorded by an earlier version: the header of a go-file tells its generator
+			entry.Generator = generatorOf(stale)
+		}
+		if _, ran := generated[entry.Generator]; !ran || !prune {
+			current[entry.Filename] = entry.Generator
+			continue
+		}
+		err = output.Remove(stale)
+		if err != nil {
+			return fmt.Errorf("Error removing stale generated file %s: %s", stale, err)
+		}
+	}
+
+	if len(current) == 0 {
+		// nothing generated (anymore): no need for a manifest
+		if len(previous) > 0 {
+			return output.Remove(ManifestFilename(dir))
+		}
+		return nil
+	}
+	return output.Write(dir, ManifestFilename(dir), manifestContent(current))
+}
+
+// manifestEntry is a generated file, relative to the dir of the package, with the generator that generated it
+type manifestEntry struct {
+	Filename  string
+	Generator string
+}
+
+func generatorOf(filename string) string {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return ""
+	}
+	header, _ := ParseHeader(content)
+	return header.Generator
+}
+
+func readManifest(filename string) ([]manifestEntry, error) {
+	f, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return []manifestEntry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error reading manifest %s: %s", filename, err)
+	}
+	defer f.Close()
+
+	entries := []manifestEntry{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// a line holds the filename and the generator, separated by a tab; earlier versions only recorded the filename
+		name, generatorName, _ := strings.Cut(line, "\t")
+		entries = append(entries, manifestEntry{Filename: strings.TrimSpace(name), Generator: strings.TrimSpace(generatorName)})
+	}
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Error reading manifest %s: %s", filename, err)
+	}
+	return entries, nil
+}
+
+func manifestContent(generators map[string]string) []byte {
+	sorted := []string{}
+	for filename := range generators {
+		sorted = append(sorted, filename)
+	}
+	sort.Strings(sorted)
+
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, manifestHeader)
+	for _, filename := range sorted {
+		if generators[filename] == "" {
+			fmt.Fprintln(&buf, filename)
+			continue
+		}
+		fmt.Fprintf(&buf, "%s\t%s\n", filename, generators[filename])
+	}
+	return buf.Bytes()
+}
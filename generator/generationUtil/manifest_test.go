@@ -0,0 +1,127 @@
+package generationUtil
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fileExists(filename string) bool {
+	_, err := os.Stat(filename)
+	return err == nil
+}
+
+// generateFiles generates the files with generator "test", like a run of that generator
+func generateFiles(t *testing.T, filenames ...string) map[string][]string {
+	recorder := NewRecorder(NewFileOutput(), Header{Generator: "test"})
+	for _, filename := range filenames {
+		assert.NoError(t, recorder.Write("testsrc", filename, []byte("content")))
+	}
+	return map[string][]string{"test": recorder.Filenames()}
+}
+
+func TestUpdateManifestRemovesStaleFiles(t *testing.T) {
+	defer os.RemoveAll("./test")
+
+	generated := generateFiles(t, "test/gen_a.go", "test/gen_b.go", "test/testStore/gen_c.go")
+	assert.NoError(t, UpdateManifest(NewFileOutput(), "test", generated, true))
+
+	data, err := ioutil.ReadFile(ManifestFilename("test"))
+	assert.NoError(t, err)
+	assert.Equal(t, manifestHeader+"\ngen_a.go\ttest\ngen_b.go\ttest\ntestStore/gen_c.go\ttest\n", string(data))
+
+	generated = generateFiles(t, "test/gen_a.go")
+	assert.NoError(t, UpdateManifest(NewFileOutput(), "test", generated, true))
+
+	assert.True(t, fileExists("test/gen_a.go"))
+	assert.False(t, fileExists("test/gen_b.go"))
+	assert.False(t, fileExists("test/testStore/gen_c.go"))
+
+	data, err = ioutil.ReadFile(ManifestFilename("test"))
+	assert.NoError(t, err)
+	assert.Equal(t, manifestHeader+"\ngen_a.go\ttest\n", string(data))
+
+	// nothing generated anymore
+	assert.NoError(t, UpdateManifest(NewFileOutput(), "test", map[string][]string{"test": {}}, true))
+	assert.False(t, fileExists("test/gen_a.go"))
+	assert.False(t, fileExists(ManifestFilename("test")))
+}
+
+func TestUpdateManifestWithoutPruning(t *testing.T) {
+	defer os.RemoveAll("./test")
+
+	generated := generateFiles(t, "test/gen_a.go", "test/gen_b.go")
+	assert.NoError(t, UpdateManifest(NewFileOutput(), "test", generated, true))
+
+	generated = generateFiles(t, "test/gen_a.go")
+	assert.NoError(t, UpdateManifest(NewFileOutput(), "test", generated, false))
+	assert.True(t, fileExists("test/gen_b.go"))
+
+	// still known as generated, so a later run can remove it
+	data, err := ioutil.ReadFile(ManifestFilename("test"))
+	assert.NoError(t, err)
+	assert.Equal(t, manifestHeader+"\ngen_a.go\ttest\ngen_b.go\ttest\n", string(data))
+}
+
+func TestUpdateManifestKeepsHandWrittenFiles(t *testing.T) {
+	defer os.RemoveAll("./test")
+
+	assert.NoError(t, os.MkdirAll("test", 0777))
+	assert.NoError(t, ioutil.WriteFile("test/handWritten.go", []byte("package test"), 0644))
+	assert.NoError(t, ioutil.WriteFile(ManifestFilename("test"), []byte("handWritten.go\n"), 0644))
+
+	assert.NoError(t, UpdateManifest(NewFileOutput(), "test", map[string][]string{"test": {}}, true))
+	assert.True(t, fileExists("test/handWritten.go"))
+}
+
+func TestUpdateManifestKeepsFilesWithoutHeader(t *testing.T) {
+	defer os.RemoveAll("./test")
+
+	generated := generateFiles(t, "test/gen_a.go", "test/gen_b.go")
+	assert.NoError(t, UpdateManifest(NewFileOutput(), "test", generated, true))
+
+	// the file is taken over by hand
+	assert.NoError(t, ioutil.WriteFile("test/gen_b.go", []byte("package test"), 0644))
+
+	generated = generateFiles(t, "test/gen_a.go")
+	assert.NoError(t, UpdateManifest(NewFileOutput(), "test", generated, true))
+	assert.True(t, fileExists("test/gen_b.go"))
+
+	data, err := ioutil.ReadFile(ManifestFilename("test"))
+	assert.NoError(t, err)
+	assert.Equal(t, manifestHeader+"\ngen_a.go\ttest\n", string(data))
+}
+
+func TestUpdateManifestKeepsFilesOfOtherGenerators(t *testing.T) {
+	defer os.RemoveAll("./test")
+
+	generated := generateFiles(t, "test/gen_a.go")
+	other := NewRecorder(NewFileOutput(), Header{Generator: "other"})
+	assert.NoError(t, other.Write("testsrc", "test/gen_b.go", []byte("content")))
+	generated["other"] = other.Filenames()
+	assert.NoError(t, UpdateManifest(NewFileOutput(), "test", generated, true))
+
+	// only generator test ran
+	assert.NoError(t, UpdateManifest(NewFileOutput(), "test", map[string][]string{"test": {}}, true))
+	assert.False(t, fileExists("test/gen_a.go"))
+	assert.True(t, fileExists("test/gen_b.go"))
+
+	data, err := ioutil.ReadFile(ManifestFilename("test"))
+	assert.NoError(t, err)
+	assert.Equal(t, manifestHeader+"\ngen_b.go\tother\n", string(data))
+}
+
+func TestUpdateManifestReadsGeneratorOfLegacyEntries(t *testing.T) {
+	defer os.RemoveAll("./test")
+
+	generateFiles(t, "test/gen_a.go")
+	other := NewRecorder(NewFileOutput(), Header{Generator: "other"})
+	assert.NoError(t, other.Write("testsrc", "test/gen_b.go", []byte("content")))
+	assert.NoError(t, ioutil.WriteFile(ManifestFilename("test"), []byte("gen_a.go\ngen_b.go\n"), 0644))
+
+	assert.NoError(t, UpdateManifest(NewFileOutput(), "test", map[string][]string{"test": {}}, true))
+	assert.False(t, fileExists("test/gen_a.go"))
+	assert.True(t, fileExists("test/gen_b.go"))
+}
@@ -0,0 +1,167 @@
+package generationUtil
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Target is an annotated package: its generated code is written into its own dir or into packages next to it
+type Target struct {
+	Dir         string
+	PackageName string
+	// ImportPath is empty when it cannot be determined, for example for code outside a module and outside GOPATH
+	ImportPath string
+	moduleDir  string
+}
+
+// DetermineTarget resolves the import-path of the package in inputDir from the go.mod of its module. Without a
+// module the import-path is derived from GOPATH.
+func DetermineTarget(inputDir string, packageName string) (Target, error) {
+	if inputDir == "" || packageName == "" {
+		return Target{}, fmt.Errorf("Input params not set")
+	}
+	target := Target{
+		Dir:         inputDir,
+		PackageName: packageName,
+	}
+
+	absDir, err := filepath.Abs(inputDir)
+	if err != nil {
+		return target, fmt.Errorf("Error determining absolute path of %s: %s", inputDir, err)
+	}
+	moduleDir, modulePath, err := findModule(absDir)
+	if err != nil {
+		return target, err
+	}
+	if moduleDir == "" {
+		moduleDir, modulePath = findGoPathRoot(absDir)
+		if moduleDir == "" {
+			return target, nil
+		}
+	}
+	rel, err := filepath.Rel(moduleDir, absDir)
+	if err != nil {
+		return target, fmt.Errorf("Error determining path of %s within %s: %s", absDir, moduleDir, err)
+	}
+	target.ImportPath = path.Join(modulePath, filepath.ToSlash(rel))
+	target.moduleDir = moduleDir
+	return target, nil
+}
+
+// DetermineTargetPath returns the dir in which the generated code of the package in inputDir is written
+func DetermineTargetPath(inputDir string, packageName string) (string, error) {
+	target, err := DetermineTarget(inputDir, packageName)
+	if err != nil {
+		return "", err
+	}
+	return target.Dir, nil
+}
+
+// Sibling returns the package with the given suffix next to the target, like <pkg>Store
+func (t Target) Sibling(suffix string) (Target, error) {
+	name := t.PackageName + suffix
+	sibling := Target{
+		Dir:         filepath.Join(t.Dir, "..", name),
+		PackageName: name,
+		moduleDir:   t.moduleDir,
+	}
+	if t.ImportPath == "" {
+		return sibling, nil
+	}
+	absDir, err := filepath.Abs(t.Dir)
+	if err != nil {
+		return sibling, fmt.Errorf("Error determining absolute path of %s: %s", t.Dir, err)
+	}
+	if absDir == t.moduleDir {
+		return sibling, fmt.Errorf("Package %s is the root of its module: package %s would be outside the module", t.PackageName, name)
+	}
+	sibling.ImportPath = path.Join(path.Dir(t.ImportPath), name)
+	return sibling, nil
+}
+
+// Sub returns the package with the given suffix within the target, like <pkg>TestLog
+func (t Target) Sub(suffix string) Target {
+	name := t.PackageName + suffix
+	sub := Target{
+		Dir:         filepath.Join(t.Dir, name),
+		PackageName: name,
+		moduleDir:   t.moduleDir,
+	}
+	if t.ImportPath != "" {
+		sub.ImportPath = path.Join(t.ImportPath, name)
+	}
+	return sub
+}
+
+// ImportSpec returns the import of the target, aliased when the package-name differs from its dir; it is empty
+// when the import-path is unknown
+func (t Target) ImportSpec() string {
+	if t.ImportPath == "" {
+		return ""
+	}
+	return importSpec(t.PackageName, t.ImportPath)
+}
+
+// findModule looks for the go.mod of the module that contains dir; an empty dir is returned when there is none
+func findModule(dir string) (string, string, error) {
+	for {
+		filename := filepath.Join(dir, "go.mod")
+		data, err := ioutil.ReadFile(filename)
+		if err == nil {
+			modulePath := parseModulePath(data)
+			if modulePath == "" {
+				return "", "", fmt.Errorf("No module-path found in %s", filename)
+			}
+			return dir, modulePath, nil
+		}
+		if !os.IsNotExist(err) {
+			return "", "", fmt.Errorf("Error reading %s: %s", filename, err)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", nil
+		}
+		dir = parent
+	}
+}
+
+func parseModulePath(data []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+		if !strings.HasPrefix(line, "module ") && !strings.HasPrefix(line, "module\t") {
+			continue
+		}
+		modulePath := strings.TrimSpace(strings.TrimPrefix(line, "module"))
+		if unquoted, err := strconv.Unquote(modulePath); err == nil {
+			modulePath = unquoted
+		}
+		return modulePath
+	}
+	return ""
+}
+
+// findGoPathRoot returns the src-dir of the GOPATH entry that contains dir; its import-path is empty
+func findGoPathRoot(dir string) (string, string) {
+	for _, goPath := range filepath.SplitList(os.Getenv("GOPATH")) {
+		if goPath == "" {
+			continue
+		}
+		src := filepath.Join(goPath, "src")
+		rel, err := filepath.Rel(src, dir)
+		if err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			return src, ""
+		}
+	}
+	return "", ""
+}
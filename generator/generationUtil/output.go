@@ -0,0 +1,191 @@
+package generationUtil
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FileOutput writes the generated files to disk. A file is only written when its content changes, and it is
+// replaced atomically so a reader never sees a half-written file.
+type FileOutput struct {
+	sync.Mutex
+	created   int
+	updated   int
+	unchanged int
+	removed   int
+}
+
+func NewFileOutput() *FileOutput {
+	return &FileOutput{}
+}
+
+func (fo *FileOutput) Write(src string, targetFilename string, content []byte) error {
+	mode := os.FileMode(0644)
+	info, err := os.Stat(targetFilename)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	exists := err == nil
+	if exists {
+		existing, err := ioutil.ReadFile(targetFilename)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(existing, content) {
+			fo.count(&fo.unchanged)
+			return nil
+		}
+		err = checkToolOwned(targetFilename, existing)
+		if err != nil {
+			return err
+		}
+		mode = info.Mode().Perm()
+	}
+
+	err = writeAtomically(targetFilename, content, mode)
+	if err != nil {
+		return fmt.Errorf("Error writing %s based on source %s: %s", targetFilename, src, err)
+	}
+	if exists {
+		fo.count(&fo.updated)
+	} else {
+		fo.count(&fo.created)
+	}
+	return nil
+}
+
+func (fo *FileOutput) Remove(filename string) error {
+	err := os.Remove(filename)
+	if err != nil {
+		return err
+	}
+	fo.count(&fo.removed)
+	return nil
+}
+
+func (fo *FileOutput) count(counter *int) {
+	fo.Lock()
+	defer fo.Unlock()
+	*counter++
+}
+
+// Summary describes what happened to the files on disk
+func (fo *FileOutput) Summary() string {
+	fo.Lock()
+	defer fo.Unlock()
+	return fmt.Sprintf("%d files created, %d updated, %d unchanged, %d removed", fo.created, fo.updated, fo.unchanged, fo.removed)
+}
+
+// checkToolOwned refuses to replace a hand-written go-file that happens to have the name of a generated file
+func checkToolOwned(filename string, existing []byte) error {
+	if strings.HasSuffix(filename, ".go") && !isToolOwnedContent(existing) {
+		return fmt.Errorf("Refusing to overwrite %s: it has no golangAnnotations header and seems to be hand-written", filename)
+	}
+	return nil
+}
+
+// writeAtomically writes to a temporary file in the same directory that is renamed to the target afterwards
+func writeAtomically(filename string, content []byte, mode os.FileMode) error {
+	dir := filepath.Dir(filename)
+	err := os.MkdirAll(dir, 0777)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(dir, "."+filepath.Base(filename)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op after a successful rename
+
+	_, err = tmp.Write(content)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(tmp.Name(), mode)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
+
+// Difference describes a generated file that is not up to date
+type Difference struct {
+	Filename string
+	Diff     string
+}
+
+// Checker is an output that leaves the files untouched: it compares the generated content with the existing files
+type Checker struct {
+	sync.Mutex
+	differences []Difference
+}
+
+func NewChecker() *Checker {
+	return &Checker{
+		differences: []Difference{},
+	}
+}
+
+func (c *Checker) Write(src string, targetFilename string, content []byte) error {
+	existing, err := ioutil.ReadFile(targetFilename)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Error reading %s: %s", targetFilename, err)
+	}
+	if bytes.Equal(existing, content) {
+		return nil
+	}
+	if existing != nil {
+		err = checkToolOwned(targetFilename, existing)
+		if err != nil {
+			return err
+		}
+	}
+	c.add(Difference{
+		Filename: targetFilename,
+		Diff:     UnifiedDiff(targetFilename, existing, content),
+	})
+	return nil
+}
+
+func (c *Checker) Remove(filename string) error {
+	existing, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error reading %s: %s", filename, err)
+	}
+	c.add(Difference{
+		Filename: filename,
+		Diff:     UnifiedDiff(filename, existing, []byte{}),
+	})
+	return nil
+}
+
+func (c *Checker) add(d Difference) {
+	c.Lock()
+	defer c.Unlock()
+	c.differences = append(c.differences, d)
+}
+
+// Differences returns the files that are not up to date, sorted by filename
+func (c *Checker) Differences() []Difference {
+	c.Lock()
+	defer c.Unlock()
+	sort.SliceStable(c.differences, func(i, j int) bool {
+		return c.differences[i].Filename < c.differences[j].Filename
+	})
+	return c.differences
+}
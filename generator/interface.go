@@ -12,5 +12,22 @@ const (
 
 type Generator interface {
 	GetAnnotations() []annotation.AnnotationDescriptor
-	Generate(inputDir string, parsedSources model.ParsedSources) error
+	Generate(inputDir string, parsedSources model.ParsedSources, output Output) error
+}
+
+// Output receives the content of every generated file and the stale generated files to be removed.
+// Every run of a generator gets its own output, so generators can run concurrently.
+type Output interface {
+	Write(src string, targetFilename string, content []byte) error
+	Remove(filename string) error
+}
+
+// Reporter is implemented by outputs that collect the diagnostics of a generator
+type Reporter interface {
+	Report(diagnostic string)
+}
+
+// TemplateProvider is implemented by generators that render built-in templates which can be overridden
+type TemplateProvider interface {
+	GetTemplates() map[string]string
 }
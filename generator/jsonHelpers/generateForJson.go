@@ -2,7 +2,7 @@ package jsonHelpers
 
 import (
 	"fmt"
-	"log"
+	"path/filepath"
 	"strings"
 	"text/template"
 	"unicode"
@@ -25,13 +25,19 @@ func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
 	return jsonAnnotation.Get()
 }
 
+func (eg *Generator) GetTemplates() map[string]string {
+	return map[string]string{
+		"json-enums": jsonHelpersTemplate,
+	}
+}
+
 type jsonContext struct {
 	PackageName string
 	Enums       []model.Enum
 	Structs     []model.Struct
 }
 
-func (eg *Generator) Generate(inputDir string, parsedSource model.ParsedSources) error {
+func (eg *Generator) Generate(inputDir string, parsedSource model.ParsedSources, output generator.Output) error {
 	enums := parsedSource.Enums
 	structs := parsedSource.Structs
 
@@ -60,7 +66,7 @@ func (eg *Generator) Generate(inputDir string, parsedSource model.ParsedSources)
 		return nil
 	}
 
-	err = doGenerate(packageName, jsonEnums, jsonStructs, targetDir)
+	err = doGenerate(packageName, jsonEnums, jsonStructs, targetDir, output)
 	if err != nil {
 		return err
 	}
@@ -68,11 +74,11 @@ func (eg *Generator) Generate(inputDir string, parsedSource model.ParsedSources)
 	return nil
 }
 
-func doGenerate(packageName string, jsonEnums []model.Enum, jsonStructs []model.Struct, targetDir string) error {
+func doGenerate(packageName string, jsonEnums []model.Enum, jsonStructs []model.Struct, targetDir string, output generator.Output) error {
 	filenameMap := getFilenamesWithTypeNames(jsonEnums, jsonStructs)
 
 	for fn := range filenameMap {
-		targetFilename := strings.Replace(fn, ".", "_json.", 1)
+		targetFilename := strings.Replace(filepath.Base(fn), ".", "_json.", 1)
 		target := generationUtil.Prefixed(fmt.Sprintf("%s/%s", targetDir, targetFilename))
 
 		data := jsonContext{
@@ -99,10 +105,10 @@ func doGenerate(packageName string, jsonEnums []model.Enum, jsonStructs []model.
 				TemplateString: jsonHelpersTemplate,
 				FuncMap:        customTemplateFuncs,
 				Data:           data,
+				Output:         output,
 			})
 			if err != nil {
-				log.Fatalf("Error generating wrappers for enums (%s)", err)
-				return err
+				return fmt.Errorf("Error generating json-helpers for %s: %w", fn, err)
 			}
 		}
 	}
@@ -134,7 +140,7 @@ func getFilenamesWithTypeNames(jsonEnums []model.Enum, jsonStructs []model.Struc
 var customTemplateFuncs = template.FuncMap{
 	"HasAlternativeName": hasAlternativeName,
 	"GetAlternativeName": getAlternativeName,
-	"GetPreferredName":   getPreferredName,
+	"GetPreferredName":   GetPreferredName,
 	"HasDefaultValue":    hasDefaultValue,
 	"GetDefaultValue":    getDefaultValue,
 	"HasSlices":          hasSlices,
@@ -216,7 +222,7 @@ func getAlternativeName(e model.Enum, lit model.EnumLiteral) string {
 	return lowerInitialIfNeeded(e, strings.TrimPrefix(name, base))
 }
 
-func getPreferredName(e model.Enum, lit model.EnumLiteral) string {
+func GetPreferredName(e model.Enum, lit model.EnumLiteral) string {
 	name := fixedLitName(lit)
 	if IsJSONEnumStripped(e) {
 		base := GetJSONEnumBase(e)
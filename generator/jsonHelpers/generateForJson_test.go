@@ -64,7 +64,7 @@ func TestGenerateForJson(t *testing.T) {
 		Enums:   e,
 		Structs: s,
 	}
-	err := NewGenerator().Generate("./testData/", ps)
+	err := NewGenerator().Generate("./testData/", ps, generationUtil.NewFileOutput())
 	assert.Nil(t, err)
 
 	// check that generated files exists
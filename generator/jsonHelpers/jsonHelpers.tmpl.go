@@ -1,13 +1,13 @@
 package jsonHelpers
 
-const jsonHelpersTemplate = `// Generated automatically by golangAnnotations: do not edit manually
-
-package {{.PackageName}}
+const jsonHelpersTemplate = `package {{.PackageName}}
 
+{{block "imports" .}}
 import (
 	"encoding/json"
 	"fmt"
 )
+{{end}}
 
 {{range .Enums}}
 {{$enum := .}}
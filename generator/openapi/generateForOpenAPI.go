@@ -0,0 +1,579 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/f0rt/golangAnnotations/config"
+	"github.com/f0rt/golangAnnotations/generator"
+	"github.com/f0rt/golangAnnotations/generator/annotation"
+	"github.com/f0rt/golangAnnotations/generator/generationUtil"
+	"github.com/f0rt/golangAnnotations/generator/jsonHelpers"
+	"github.com/f0rt/golangAnnotations/generator/jsonHelpers/jsonAnnotation"
+	"github.com/f0rt/golangAnnotations/generator/rest"
+	"github.com/f0rt/golangAnnotations/generator/rest/restAnnotation"
+	"github.com/f0rt/golangAnnotations/model"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	openAPIVersion = "3.1.0"
+
+	// errorSchemaName is the schema of the error-responses written by errorh.HandleHTTPError
+	errorSchemaName = "errorh.Error"
+
+	// rolesSchemeName is the configured security-scheme under which the roles of an operation are listed
+	rolesSchemeName = "roles"
+)
+
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return append(restAnnotation.Get(), jsonAnnotation.Get()...)
+}
+
+func (eg *Generator) Generate(inputDir string, parsedSources model.ParsedSources, output generator.Output) error {
+	packageName, err := generationUtil.GetPackageNameForStructs(parsedSources.Structs)
+	if packageName == "" || err != nil {
+		return err
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(inputDir, packageName)
+	if err != nil {
+		return err
+	}
+
+	cfg := generationUtil.GetConfig()
+	formats, err := getFormats(cfg.Option("openapi", config.OptionOpenAPIFormats))
+	if err != nil {
+		return err
+	}
+	scheme, err := getSecurityScheme(cfg.Option("openapi", config.OptionOpenAPISecurityScheme))
+	if err != nil {
+		return err
+	}
+
+	for _, service := range parsedSources.Structs {
+		if !rest.IsRestService(service) {
+			continue
+		}
+		doc := newBuilder(parsedSources).build(service, cfg.Option("openapi", config.OptionOpenAPIVersion), scheme)
+		for _, format := range formats {
+			content, err := marshal(doc, format)
+			if err != nil {
+				return fmt.Errorf("Error marshalling OpenAPI document of service %s: %s", service.Name, err)
+			}
+			err = output.Write(
+				fmt.Sprintf("%s.%s", service.PackageName, rest.ToFirstUpper(service.Name)),
+				generationUtil.Prefixed(fmt.Sprintf("%s/%s.openapi.%s", targetDir, rest.ToFirstUpper(service.Name), format)),
+				content)
+			if err != nil {
+				return fmt.Errorf("Error writing OpenAPI document of service %s: %s", service.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func getFormats(formatsOption string) ([]string, error) {
+	formats := []string{}
+	for _, format := range strings.Split(formatsOption, ",") {
+		format = strings.TrimSpace(format)
+		switch format {
+		case "":
+		case "json", "yaml":
+			formats = append(formats, format)
+		default:
+			return nil, fmt.Errorf("Unsupported OpenAPI format '%s': use json and/or yaml", format)
+		}
+	}
+	if len(formats) == 0 {
+		formats = append(formats, "json")
+	}
+	return formats, nil
+}
+
+// getSecurityScheme parses a security-scheme like http:bearer or apiKey:header:X-Api-Key. The generated handlers
+// leave authentication to the application, so there is no scheme unless it is configured.
+func getSecurityScheme(schemeOption string) (*securityScheme, error) {
+	if schemeOption == "" {
+		return nil, nil
+	}
+	parts := strings.Split(schemeOption, ":")
+	switch {
+	case parts[0] == "http" && len(parts) == 2 && parts[1] != "":
+		return &securityScheme{Type: "http", Scheme: parts[1]}, nil
+	case parts[0] == "apiKey" && len(parts) == 3 && (parts[1] == "header" || parts[1] == "query" || parts[1] == "cookie") && parts[2] != "":
+		return &securityScheme{Type: "apiKey", In: parts[1], Name: parts[2]}, nil
+	}
+	return nil, fmt.Errorf("Unsupported OpenAPI security-scheme '%s': use http:<scheme> or apiKey:<header|query|cookie>:<name>", schemeOption)
+}
+
+func marshal(doc document, format string) ([]byte, error) {
+	if format == "yaml" {
+		return yaml.Marshal(doc)
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "\t")
+	err := encoder.Encode(doc)
+	return buf.Bytes(), err
+}
+
+type document struct {
+	OpenAPI    string              `json:"openapi" yaml:"openapi"`
+	Info       info                `json:"info" yaml:"info"`
+	Paths      map[string]pathItem `json:"paths" yaml:"paths"`
+	Components components          `json:"components" yaml:"components"`
+}
+
+type info struct {
+	Title       string `json:"title" yaml:"title"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Version     string `json:"version" yaml:"version"`
+}
+
+// pathItem holds the operations of a path per lowercase http-method
+type pathItem map[string]*operation
+
+type operation struct {
+	OperationID string                `json:"operationId" yaml:"operationId"`
+	Description string                `json:"description,omitempty" yaml:"description,omitempty"`
+	Parameters  []parameter           `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	RequestBody *requestBody          `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+	Responses   map[string]response   `json:"responses" yaml:"responses"`
+	Security    []map[string][]string `json:"security,omitempty" yaml:"security,omitempty"`
+	Roles       []string              `json:"x-roles,omitempty" yaml:"x-roles,omitempty"`
+}
+
+type parameter struct {
+	Name     string  `json:"name" yaml:"name"`
+	In       string  `json:"in" yaml:"in"`
+	Required bool    `json:"required,omitempty" yaml:"required,omitempty"`
+	Schema   *schema `json:"schema" yaml:"schema"`
+}
+
+type requestBody struct {
+	Required bool                 `json:"required,omitempty" yaml:"required,omitempty"`
+	Content  map[string]mediaType `json:"content" yaml:"content"`
+}
+
+type response struct {
+	Description string               `json:"description" yaml:"description"`
+	Headers     map[string]header    `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Content     map[string]mediaType `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+type header struct {
+	Description string  `json:"description,omitempty" yaml:"description,omitempty"`
+	Schema      *schema `json:"schema" yaml:"schema"`
+}
+
+type mediaType struct {
+	Schema *schema `json:"schema" yaml:"schema"`
+}
+
+type schema struct {
+	Ref                  string             `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty" yaml:"type,omitempty"`
+	Format               string             `json:"format,omitempty" yaml:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Items                *schema            `json:"items,omitempty" yaml:"items,omitempty"`
+	Properties           map[string]*schema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	AdditionalProperties *schema            `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty" yaml:"required,omitempty"`
+	Enum                 []string           `json:"enum,omitempty" yaml:"enum,omitempty"`
+}
+
+type components struct {
+	Schemas         map[string]*schema        `json:"schemas" yaml:"schemas"`
+	SecuritySchemes map[string]securityScheme `json:"securitySchemes,omitempty" yaml:"securitySchemes,omitempty"`
+}
+
+type securityScheme struct {
+	Type        string `json:"type" yaml:"type"`
+	Scheme      string `json:"scheme,omitempty" yaml:"scheme,omitempty"`
+	In          string `json:"in,omitempty" yaml:"in,omitempty"`
+	Name        string `json:"name,omitempty" yaml:"name,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// builder derives the schemas of the types that the operations use from the structs, enums and typedefs of the
+// package. Types of other packages are described by an empty schema: they can be anything.
+type builder struct {
+	structs  map[string]model.Struct
+	enums    map[string]model.Enum
+	typedefs map[string]model.Typedef
+	schemas  map[string]*schema
+	argTypes rest.ArgTypes
+}
+
+func newBuilder(parsedSources model.ParsedSources) *builder {
+	b := &builder{
+		structs:  map[string]model.Struct{},
+		enums:    map[string]model.Enum{},
+		typedefs: map[string]model.Typedef{},
+		schemas:  map[string]*schema{},
+		argTypes: rest.NewArgTypes(parsedSources.Structs, parsedSources.Enums),
+	}
+	for _, s := range parsedSources.Structs {
+		b.structs[s.Name] = s
+	}
+	for _, e := range parsedSources.Enums {
+		b.enums[e.Name] = e
+	}
+	for _, td := range parsedSources.Typedefs {
+		b.typedefs[td.Name] = td
+	}
+	return b
+}
+
+func (b *builder) build(service model.Struct, version string, scheme *securityScheme) document {
+	doc := document{
+		OpenAPI: openAPIVersion,
+		Info: info{
+			Title:       service.Name,
+			Description: getDescription(service.DocLines),
+			Version:     version,
+		},
+		Paths: map[string]pathItem{},
+	}
+
+	b.schemas[errorSchemaName] = errorSchema()
+	hasRoles := false
+	for _, o := range service.Operations {
+		if !rest.IsRestOperation(*o) {
+			continue
+		}
+		path := rest.StripPathPatterns(rest.GetRestServicePath(service) + rest.GetRestOperationPath(*o))
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = pathItem{}
+		}
+		oper := b.operation(*o)
+		if len(oper.Roles) > 0 && scheme != nil {
+			oper.Security = []map[string][]string{{rolesSchemeName: oper.Roles}}
+			hasRoles = true
+		}
+		doc.Paths[path][strings.ToLower(rest.GetRestOperationMethod(*o))] = oper
+	}
+
+	doc.Components.Schemas = b.schemas
+	if hasRoles {
+		rolesScheme := *scheme
+		rolesScheme.Description = "The authenticated user must have one of the listed roles"
+		doc.Components.SecuritySchemes = map[string]securityScheme{rolesSchemeName: rolesScheme}
+	}
+	return doc
+}
+
+func (b *builder) operation(o model.Operation) *operation {
+	oper := &operation{
+		OperationID: o.Name,
+		Description: getDescription(o.DocLines),
+		Parameters:  []parameter{},
+		Responses:   map[string]response{},
+	}
+
+	formParams := &schema{Type: "object", Properties: map[string]*schema{}}
+	isForm := rest.IsRestOperationForm(o) && rest.IsClientBodyMethod(o)
+	for _, arg := range o.InputArgs {
+		if rest.IsContextArg(arg) || rest.IsRequestContextArg(arg) || rest.IsUploadArg(arg) {
+			continue
+		}
+		if b.argTypes.IsQueryStructArg(o, arg) {
+			for _, field := range b.argTypes.GetQueryFields(arg) {
+				oper.Parameters = append(oper.Parameters, parameter{Name: field.Param, In: "query", Required: field.Required, Schema: b.paramSchemaFor(field.Field.TypeName)})
+			}
+			continue
+		}
+		if b.argTypes.IsCustomArg(arg) {
+			if b.argTypes.IsInputArg(arg) && b.argTypes.HasInput(o) && !rest.HasUpload(o) {
+				oper.RequestBody = &requestBody{
+					Required: true,
+					Content:  map[string]mediaType{"application/json": {Schema: b.schemaFor(arg.TypeName)}},
+				}
+			}
+			continue
+		}
+		name := rest.GetParamName(o, arg)
+		switch {
+		case rest.IsPathParam(o, arg):
+			oper.Parameters = append(oper.Parameters, parameter{Name: name, In: "path", Required: true, Schema: b.pathParamSchemaFor(o, arg)})
+		case rest.IsHeaderParam(o, arg):
+			oper.Parameters = append(oper.Parameters, parameter{Name: name, In: "header", Required: rest.IsInputArgMandatory(o, arg), Schema: b.paramSchemaFor(arg.TypeName)})
+		case rest.IsCookieParam(o, arg):
+			oper.Parameters = append(oper.Parameters, parameter{Name: name, In: "cookie", Required: rest.IsInputArgMandatory(o, arg), Schema: b.paramSchemaFor(arg.TypeName)})
+		case isForm:
+			formParams.Properties[name] = b.paramSchemaFor(arg.TypeName)
+			if rest.IsInputArgMandatory(o, arg) {
+				formParams.Required = append(formParams.Required, name)
+			}
+		default:
+			oper.Parameters = append(oper.Parameters, parameter{Name: name, In: "query", Required: rest.IsInputArgMandatory(o, arg), Schema: b.paramSchemaFor(arg.TypeName)})
+		}
+	}
+	if rest.HasUpload(o) {
+		oper.RequestBody = &requestBody{
+			Required: true,
+			Content:  map[string]mediaType{"multipart/form-data": {Schema: &schema{Type: "object"}}},
+		}
+	} else if isForm && len(formParams.Properties) > 0 {
+		oper.RequestBody = &requestBody{
+			Required: true,
+			Content:  map[string]mediaType{"application/x-www-form-urlencoded": {Schema: formParams}},
+		}
+	}
+
+	oper.Responses[successStatus(o)] = b.successResponse(o)
+	oper.Responses["default"] = response{
+		Description: "Error",
+		Content:     map[string]mediaType{"application/json": {Schema: &schema{Ref: schemaRef(errorSchemaName)}}},
+	}
+
+	if roles := rest.GetRestOperationRoles(o); len(roles) > 0 {
+		oper.Roles = roles
+	}
+	return oper
+}
+
+func successStatus(o model.Operation) string {
+	return strconv.Itoa(rest.GetRestOperationStatus(o))
+}
+
+func (b *builder) successResponse(o model.Operation) response {
+	resp := response{Description: http.StatusText(rest.GetRestOperationStatus(o))}
+	if rest.HasRestOperationLocation(o) {
+		resp.Headers = map[string]header{"Location": {Description: "The location of the result", Schema: &schema{Type: "string", Format: "uri-reference"}}}
+	}
+	if rest.IsRestOperationNoContent(o) {
+		return resp
+	}
+	if !rest.HasOutput(o) || !rest.HasContentType(o) {
+		return resp
+	}
+	resp.Content = map[string]mediaType{}
+	for _, format := range rest.GetRestOperationFormats(o) {
+		s := &schema{Type: "string"}
+		if format == "JSON" || format == "XML" {
+			s = b.schemaFor(rest.GetOutputArgType(o))
+		}
+		resp.Content[rest.GetFormatContentType(format)] = mediaType{Schema: s}
+	}
+	return resp
+}
+
+func schemaRef(name string) string {
+	return "#/components/schemas/" + name
+}
+
+// paramSchemaFor returns the schema of a parameter; unlike in json, a duration-parameter is passed like "1h30m"
+func (b *builder) paramSchemaFor(typeName string) *schema {
+	if typeName == "time.Duration" {
+		return &schema{Type: "string", Format: "duration"}
+	}
+	return b.schemaFor(typeName)
+}
+
+// pathParamSchemaFor returns the schema of a path-parameter, with the regular expression that its value must match
+func (b *builder) pathParamSchemaFor(o model.Operation, arg model.Field) *schema {
+	s := b.paramSchemaFor(arg.TypeName)
+	if pattern := rest.GetPathParamPattern(o, arg.Name); pattern != "" {
+		// gorilla/mux matches the complete value of the variable
+		s.Pattern = "^(?:" + pattern + ")$"
+	}
+	return s
+}
+
+// schemaFor returns the schema of a golang type-name, like "*Person", "[]string" or "map[string]Address"
+func (b *builder) schemaFor(typeName string) *schema {
+	typeName = strings.TrimPrefix(typeName, "*")
+	field := model.Field{TypeName: typeName}
+	if field.IsSlice() {
+		if field.SliceElementTypeName() == "byte" {
+			return &schema{Type: "string", Format: "byte"}
+		}
+		return &schema{Type: "array", Items: b.schemaFor(field.SliceElementTypeName())}
+	}
+	if field.IsMap() {
+		_, valueTypeName := field.SplitMapTypeNames()
+		return &schema{Type: "object", AdditionalProperties: b.schemaFor(valueTypeName)}
+	}
+
+	switch typeName {
+	case "string":
+		return &schema{Type: "string"}
+	case "bool":
+		return &schema{Type: "boolean"}
+	case "int", "int8", "int16", "uint", "uint8", "uint16":
+		return &schema{Type: "integer"}
+	case "int32", "uint32":
+		return &schema{Type: "integer", Format: "int32"}
+	case "int64", "uint64":
+		return &schema{Type: "integer", Format: "int64"}
+	case "float32":
+		return &schema{Type: "number", Format: "float"}
+	case "float64":
+		return &schema{Type: "number", Format: "double"}
+	case "time.Time":
+		return &schema{Type: "string", Format: "date-time"}
+	case "mydate.MyDate":
+		return &schema{Type: "string", Format: "date"}
+	}
+
+	if b.define(typeName) {
+		return &schema{Ref: schemaRef(typeName)}
+	}
+	return &schema{}
+}
+
+// define adds the schema of a type of the package to the components; it returns false for unknown types
+func (b *builder) define(typeName string) bool {
+	if _, ok := b.schemas[typeName]; ok {
+		return true
+	}
+	if s, ok := b.structs[typeName]; ok {
+		// registered before its fields are described, so that recursive types refer to themselves
+		b.schemas[typeName] = &schema{Type: "object"}
+		b.describeStruct(b.schemas[typeName], s)
+		return true
+	}
+	if e, ok := b.enums[typeName]; ok {
+		b.schemas[typeName] = enumSchema(e)
+		return true
+	}
+	if td, ok := b.typedefs[typeName]; ok {
+		b.schemas[typeName] = &schema{}
+		*b.schemas[typeName] = *b.schemaFor(td.Type)
+		return true
+	}
+	return false
+}
+
+func (b *builder) describeStruct(target *schema, s model.Struct) {
+	target.Properties = map[string]*schema{}
+	b.describeFields(target, s, false, map[string]bool{s.Name: true})
+}
+
+// describeFields adds the fields of the struct to the properties. Like encoding/json, it promotes the fields of an
+// embedded struct without json-name, unless a field of the embedding struct has the same name; the fields of an
+// embedded pointer are optional.
+func (b *builder) describeFields(target *schema, s model.Struct, optional bool, embedding map[string]bool) {
+	embedded := []model.Field{}
+	for _, f := range s.Fields {
+		if f.Name == "" {
+			typeName := strings.TrimPrefix(f.TypeName, "*")
+			if _, ok := b.structs[typeName]; ok && !hasJSONName(f) {
+				embedded = append(embedded, f)
+				continue
+			}
+			// other embedded types are encoded like a field that has the name of the type
+			f.Name = typeName[strings.LastIndex(typeName, ".")+1:]
+		}
+		if !isExported(f.Name) {
+			continue
+		}
+		name, omitEmpty := getJSONName(f)
+		if _, ok := target.Properties[name]; ok || name == "-" {
+			continue
+		}
+		target.Properties[name] = b.schemaFor(f.TypeName)
+		if !omitEmpty && !f.IsPointer() && !optional {
+			target.Required = append(target.Required, name)
+		}
+	}
+	for _, f := range embedded {
+		typeName := strings.TrimPrefix(f.TypeName, "*")
+		if embedding[typeName] {
+			continue
+		}
+		embedding[typeName] = true
+		b.describeFields(target, b.structs[typeName], optional || f.IsPointer(), embedding)
+		delete(embedding, typeName)
+	}
+}
+
+func hasJSONName(f model.Field) bool {
+	tag, ok := f.GetTagMap()["json"]
+	return ok && strings.Split(tag, ",")[0] != ""
+}
+
+func enumSchema(e model.Enum) *schema {
+	if !jsonHelpers.IsJSONEnum(e) {
+		return &schema{Type: "integer"}
+	}
+	s := &schema{Type: "string", Enum: []string{}}
+	for _, lit := range e.EnumLiterals {
+		s.Enum = append(s.Enum, jsonHelpers.GetPreferredName(e, lit))
+	}
+	return s
+}
+
+func errorSchema() *schema {
+	return &schema{
+		Type: "object",
+		Properties: map[string]*schema{
+			"errorMessage": {Type: "string"},
+			"errorCode":    {Type: "integer"},
+			"fieldErrors": {
+				Type: "array",
+				Items: &schema{
+					Type: "object",
+					Properties: map[string]*schema{
+						"subCode": {Type: "integer"},
+						"field":   {Type: "string"},
+						"msg":     {Type: "string"},
+					},
+				},
+			},
+		},
+		Required: []string{"errorMessage", "errorCode"},
+	}
+}
+
+func isExported(name string) bool {
+	return strings.ToUpper(name[:1]) == name[:1]
+}
+
+// getJSONName returns the name of a field in json, as encoding/json determines it from the json-tag
+func getJSONName(f model.Field) (string, bool) {
+	tag, ok := f.GetTagMap()["json"]
+	if !ok {
+		return f.Name, false
+	}
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = f.Name
+	}
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			return name, true
+		}
+	}
+	return name, false
+}
+
+var annotationLine = regexp.MustCompile(`^\s*@`)
+
+// getDescription returns the doc-lines without the comment-markers and annotations
+func getDescription(docLines []string) string {
+	lines := []string{}
+	for _, line := range docLines {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "//"))
+		if line == "" || annotationLine.MatchString(line) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,296 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/f0rt/golangAnnotations/config"
+	"github.com/f0rt/golangAnnotations/generator/generationUtil"
+	"github.com/f0rt/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.RemoveAll("./testData")
+}
+
+func createSources() model.ParsedSources {
+	return model.ParsedSources{
+		Structs: []model.Struct{
+			{
+				PackageName: "testData",
+				DocLines:    []string{"// MyService manages persons", `// @RestService( path = "/api")`},
+				Name:        "MyService",
+				Operations: []*model.Operation{
+					{
+						DocLines:      []string{`// @RestOperation( method = "GET", path = "/person/{personUID}", format = "JSON,XML,CSV", optionalargs = "verbose,gender,timeout", headers = "X-Tenant-ID:tenantID" )`},
+						Name:          "getPerson",
+						RelatedStruct: &model.Field{TypeName: "MyService"},
+						InputArgs: []model.Field{
+							{Name: "c", TypeName: "context.Context"},
+							{Name: "personUID", TypeName: "string"},
+							{Name: "verbose", TypeName: "bool"},
+							{Name: "gender", TypeName: "Gender"},
+							{Name: "timeout", TypeName: "time.Duration"},
+							{Name: "filter", TypeName: "PersonFilter"},
+							{Name: "tenantID", TypeName: "string"},
+						},
+						OutputArgs: []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+					},
+					{
+						DocLines:      []string{`// @RestOperation( method = "POST", path = "/person", format = "JSON", roles = "admin, editor", status = "201", location = "/person/{person.UID}" )`},
+						Name:          "createPerson",
+						RelatedStruct: &model.Field{TypeName: "MyService"},
+						InputArgs: []model.Field{
+							{Name: "c", TypeName: "context.Context"},
+							{Name: "person", TypeName: "Person"},
+						},
+						OutputArgs: []model.Field{{TypeName: "error"}},
+					},
+				},
+			},
+			{
+				PackageName: "testData",
+				Name:        "Person",
+				Fields: []model.Field{
+					{Name: "Name", TypeName: "string", Tag: "`json:\"name\"`"},
+					{Name: "Gender", TypeName: "Gender", Tag: "`json:\"gender,omitempty\"`"},
+					{Name: "Children", TypeName: "[]Person", Tag: "`json:\"children,omitempty\"`"},
+					{Name: "Secret", TypeName: "string", Tag: "`json:\"-\"`"},
+					{Name: "internal", TypeName: "string"},
+				},
+			},
+			{
+				PackageName: "testData",
+				Name:        "PersonFilter",
+				Fields: []model.Field{
+					{Name: "Name", TypeName: "string", Tag: "`query:\"name,required\"`"},
+					{Name: "Tags", TypeName: "[]string", Tag: "`query:\"tag\"`"},
+				},
+			},
+		},
+		Enums: []model.Enum{
+			{
+				PackageName: "testData",
+				DocLines:    []string{"// @JsonEnum()"},
+				Name:        "Gender",
+				EnumLiterals: []model.EnumLiteral{
+					{Name: "Male"},
+					{Name: "Female"},
+				},
+			},
+		},
+	}
+}
+
+func TestGenerateForOpenAPI(t *testing.T) {
+	cleanup()
+	defer cleanup()
+	assert.NoError(t, os.MkdirAll("testData", 0777))
+
+	err := NewGenerator().Generate("testData", createSources(), generationUtil.NewFileOutput())
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("testData/MyService.openapi.json"))
+	assert.NoError(t, err)
+
+	var doc document
+	assert.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, "3.1.0", doc.OpenAPI)
+	assert.Equal(t, "MyService", doc.Info.Title)
+	assert.Equal(t, "MyService manages persons", doc.Info.Description)
+
+	getPerson := doc.Paths["/api/person/{personUID}"]["get"]
+	if assert.NotNil(t, getPerson) {
+		assert.Equal(t, []parameter{
+			{Name: "personUID", In: "path", Required: true, Schema: &schema{Type: "string"}},
+			{Name: "verbose", In: "query", Schema: &schema{Type: "boolean"}},
+			{Name: "gender", In: "query", Schema: &schema{Ref: "#/components/schemas/Gender"}},
+			{Name: "timeout", In: "query", Schema: &schema{Type: "string", Format: "duration"}},
+			{Name: "name", In: "query", Required: true, Schema: &schema{Type: "string"}},
+			{Name: "tag", In: "query", Schema: &schema{Type: "array", Items: &schema{Type: "string"}}},
+			{Name: "X-Tenant-ID", In: "header", Required: true, Schema: &schema{Type: "string"}},
+		}, getPerson.Parameters)
+		assert.Equal(t, "#/components/schemas/Person", getPerson.Responses["200"].Content["application/json"].Schema.Ref)
+		assert.Equal(t, "#/components/schemas/Person", getPerson.Responses["200"].Content["application/xml; charset=UTF-8"].Schema.Ref)
+		assert.Equal(t, "string", getPerson.Responses["200"].Content["text/csv; charset=UTF-8"].Schema.Type)
+		assert.Equal(t, "#/components/schemas/errorh.Error", getPerson.Responses["default"].Content["application/json"].Schema.Ref)
+		assert.Empty(t, getPerson.Security)
+	}
+
+	createPerson := doc.Paths["/api/person"]["post"]
+	if assert.NotNil(t, createPerson) {
+		assert.Equal(t, "#/components/schemas/Person", createPerson.RequestBody.Content["application/json"].Schema.Ref)
+		assert.Equal(t, []string{"admin", "editor"}, createPerson.Roles)
+		assert.Empty(t, createPerson.Security)
+		assert.Empty(t, doc.Components.SecuritySchemes)
+		assert.Equal(t, "Created", createPerson.Responses["201"].Description)
+		assert.Equal(t, "string", createPerson.Responses["201"].Headers["Location"].Schema.Type)
+	}
+
+	person := doc.Components.Schemas["Person"]
+	if assert.NotNil(t, person) {
+		assert.Equal(t, []string{"name"}, person.Required)
+		assert.Len(t, person.Properties, 3)
+		assert.Equal(t, "#/components/schemas/Gender", person.Properties["gender"].Ref)
+		assert.Equal(t, "#/components/schemas/Person", person.Properties["children"].Items.Ref)
+	}
+	assert.Equal(t, &schema{Type: "string", Enum: []string{"male", "female"}}, doc.Components.Schemas["Gender"])
+}
+
+func TestGenerateForOpenAPIWithPathPatternsAndEmbeddedStructs(t *testing.T) {
+	cleanup()
+	defer cleanup()
+	assert.NoError(t, os.MkdirAll("testData", 0777))
+
+	sources := model.ParsedSources{
+		Structs: []model.Struct{
+			{
+				PackageName: "testData",
+				DocLines:    []string{`// @RestService( path = "/api")`},
+				Name:        "MyService",
+				Operations: []*model.Operation{
+					{
+						DocLines:      []string{`// @RestOperation( method = "PUT", path = "/person/{id:[0-9]{1,8}}/{name}", format = "JSON" )`},
+						Name:          "updatePerson",
+						RelatedStruct: &model.Field{TypeName: "MyService"},
+						InputArgs: []model.Field{
+							{Name: "id", TypeName: "int"},
+							{Name: "name", TypeName: "string"},
+							{Name: "person", TypeName: "Person"},
+						},
+						OutputArgs: []model.Field{{TypeName: "error"}},
+					},
+				},
+			},
+			{
+				PackageName: "testData",
+				Name:        "Person",
+				Fields: []model.Field{
+					{TypeName: "Base"},
+					{TypeName: "*Audit"},
+					{TypeName: "Named", Tag: "`json:\"named\"`"},
+					{Name: "Name", TypeName: "string", Tag: "`json:\"name\"`"},
+				},
+			},
+			{
+				PackageName: "testData",
+				Name:        "Base",
+				Fields: []model.Field{
+					{Name: "UID", TypeName: "string", Tag: "`json:\"uid\"`"},
+					{Name: "Name", TypeName: "int", Tag: "`json:\"name\"`"},
+				},
+			},
+			{
+				PackageName: "testData",
+				Name:        "Audit",
+				Fields:      []model.Field{{Name: "CreatedBy", TypeName: "string", Tag: "`json:\"createdBy\"`"}},
+			},
+			{
+				PackageName: "testData",
+				Name:        "Named",
+				Fields:      []model.Field{{Name: "Value", TypeName: "string", Tag: "`json:\"value\"`"}},
+			},
+		},
+	}
+	err := NewGenerator().Generate("testData", sources, generationUtil.NewFileOutput())
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("testData/MyService.openapi.json"))
+	assert.NoError(t, err)
+	var doc document
+	assert.NoError(t, json.Unmarshal(data, &doc))
+
+	updatePerson := doc.Paths["/api/person/{id}/{name}"]["put"]
+	if assert.NotNil(t, updatePerson) {
+		assert.Equal(t, []parameter{
+			{Name: "id", In: "path", Required: true, Schema: &schema{Type: "integer", Pattern: "^(?:[0-9]{1,8})$"}},
+			{Name: "name", In: "path", Required: true, Schema: &schema{Type: "string"}},
+		}, updatePerson.Parameters)
+	}
+
+	person := doc.Components.Schemas["Person"]
+	if assert.NotNil(t, person) {
+		assert.Equal(t, map[string]*schema{
+			"uid":       {Type: "string"},
+			"name":      {Type: "string"},
+			"createdBy": {Type: "string"},
+			"named":     {Ref: "#/components/schemas/Named"},
+		}, person.Properties)
+		assert.ElementsMatch(t, []string{"named", "name", "uid"}, person.Required)
+	}
+}
+
+func TestGenerateForOpenAPIAsYaml(t *testing.T) {
+	cleanup()
+	defer cleanup()
+	assert.NoError(t, os.MkdirAll("testData", 0777))
+
+	cfg := config.Default()
+	cfg.Options["openapi"][config.OptionOpenAPIFormats] = "yaml"
+	generationUtil.SetConfig(cfg)
+	defer generationUtil.SetConfig(config.Default())
+
+	err := NewGenerator().Generate("testData", createSources(), generationUtil.NewFileOutput())
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("testData/MyService.openapi.yaml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "openapi: 3.1.0\n")
+	assert.Contains(t, string(data), "$ref: '#/components/schemas/Person'")
+	assert.False(t, fileExists(generationUtil.Prefixed("testData/MyService.openapi.json")))
+}
+
+func TestGenerateForOpenAPIWithSecurityScheme(t *testing.T) {
+	cleanup()
+	defer cleanup()
+	assert.NoError(t, os.MkdirAll("testData", 0777))
+
+	cfg := config.Default()
+	cfg.Options["openapi"][config.OptionOpenAPISecurityScheme] = "http:bearer"
+	generationUtil.SetConfig(cfg)
+	defer generationUtil.SetConfig(config.Default())
+
+	err := NewGenerator().Generate("testData", createSources(), generationUtil.NewFileOutput())
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("testData/MyService.openapi.json"))
+	assert.NoError(t, err)
+	var doc document
+	assert.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, []map[string][]string{{"roles": {"admin", "editor"}}}, doc.Paths["/api/person"]["post"].Security)
+	assert.Empty(t, doc.Paths["/api/person/{personUID}"]["get"].Security)
+	assert.Equal(t, "http", doc.Components.SecuritySchemes["roles"].Type)
+	assert.Equal(t, "bearer", doc.Components.SecuritySchemes["roles"].Scheme)
+}
+
+func TestGetSecurityScheme(t *testing.T) {
+	scheme, err := getSecurityScheme("")
+	assert.NoError(t, err)
+	assert.Nil(t, scheme)
+
+	scheme, err = getSecurityScheme("apiKey:header:X-Api-Key")
+	assert.NoError(t, err)
+	assert.Equal(t, &securityScheme{Type: "apiKey", In: "header", Name: "X-Api-Key"}, scheme)
+
+	for _, option := range []string{"bearer", "http:", "apiKey:body:x", "apiKey:header"} {
+		_, err = getSecurityScheme(option)
+		assert.Error(t, err, option)
+	}
+}
+
+func TestGetFormats(t *testing.T) {
+	formats, err := getFormats(" json, yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"json", "yaml"}, formats)
+
+	_, err = getFormats("xml")
+	assert.Error(t, err)
+}
+
+func fileExists(filename string) bool {
+	_, err := os.Stat(filename)
+	return err == nil
+}
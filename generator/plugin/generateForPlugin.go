@@ -0,0 +1,174 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/f0rt/golangAnnotations/generator"
+	"github.com/f0rt/golangAnnotations/generator/annotation"
+	"github.com/f0rt/golangAnnotations/generator/generationUtil"
+	"github.com/f0rt/golangAnnotations/model"
+)
+
+type Generator struct {
+	name       string
+	executable string
+	options    map[string]string
+}
+
+func NewGenerator(name string, executable string, options map[string]string) generator.Generator {
+	return &Generator{
+		name:       name,
+		executable: executable,
+		options:    options,
+	}
+}
+
+func (pg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	// annotations are interpreted by the plugin itself
+	return []annotation.AnnotationDescriptor{}
+}
+
+func (pg *Generator) Generate(inputDir string, parsedSources model.ParsedSources, output generator.Output) error {
+	resp, err := pg.invoke(Request{
+		ProtocolVersion: ProtocolVersion,
+		InputDir:        inputDir,
+		Options:         pg.options,
+		ParsedSources:   parsedSources,
+	})
+	if err != nil {
+		return err
+	}
+
+	errs := []string{}
+	for _, d := range resp.Diagnostics {
+		report(output, fmt.Sprintf("plugin %s: %s", pg.name, d))
+		if d.Severity == SeverityError {
+			errs = append(errs, d.String())
+		}
+	}
+	if resp.Error != "" {
+		return fmt.Errorf("Plugin %s failed: %s", pg.name, resp.Error)
+	}
+	if len(errs) > 0 {
+		// the files of a failing plugin are not written, just like those of a failing built-in generator
+		return fmt.Errorf("Plugin %s reported errors: %s", pg.name, strings.Join(errs, "; "))
+	}
+
+	for _, f := range resp.Files {
+		target, err := targetFilename(inputDir, f.Name)
+		if err != nil {
+			return fmt.Errorf("Plugin %s: %s", pg.name, err)
+		}
+		err = output.Write(fmt.Sprintf("plugin %s", pg.name), target, []byte(f.Content))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// report passes a diagnostic to the output when it collects them, and prints it otherwise
+func report(output generator.Output, diagnostic string) {
+	if reporter, ok := output.(generator.Reporter); ok {
+		reporter.Report(diagnostic)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s: %s\n", "golangAnnotations", diagnostic)
+}
+
+func (pg *Generator) invoke(req Request) (Response, error) {
+	input, err := json.Marshal(req)
+	if err != nil {
+		return Response{}, fmt.Errorf("Error encoding request for plugin %s: %s", pg.name, err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(pg.executable)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if err != nil {
+		return Response{}, fmt.Errorf("Error running plugin %s (%s): %s: %s", pg.name, pg.executable, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var resp Response
+	err = json.Unmarshal(stdout.Bytes(), &resp)
+	if err != nil {
+		return Response{}, fmt.Errorf("Error decoding response of plugin %s: %s", pg.name, err)
+	}
+	return resp, nil
+}
+
+// targetFilename makes sure the plugin writes within the input-dir and sticks to the naming convention
+// of generated files, so that these are never parsed as input.
+func targetFilename(inputDir string, name string) (string, error) {
+	if name == "" || filepath.IsAbs(name) {
+		return "", fmt.Errorf("Invalid filename '%s': must be relative to the input-dir", name)
+	}
+	name = path.Clean(filepath.ToSlash(name))
+	if name == "." || name == ".." || strings.HasPrefix(name, "../") {
+		return "", fmt.Errorf("Invalid filename '%s': must be within the input-dir", name)
+	}
+	if !generationUtil.IsGenerated(name) {
+		name = generationUtil.Prefixed(name)
+	}
+	return path.Join(inputDir, name), nil
+}
+
+// Plugin describes an executable that was found on the search-path
+type Plugin struct {
+	Name       string
+	Executable string
+}
+
+// Discover finds all plugin executables in the directories of the PATH environment-variable.
+// When a plugin is found multiple times, the first one wins, just as for the shell.
+func Discover() []Plugin {
+	return discoverIn(filepath.SplitList(os.Getenv("PATH")))
+}
+
+func discoverIn(dirs []string) []Plugin {
+	plugins := make([]Plugin, 0)
+	found := map[string]bool{}
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := pluginName(entry)
+			if !ok || found[name] {
+				continue
+			}
+			found[name] = true
+			plugins = append(plugins, Plugin{
+				Name:       name,
+				Executable: filepath.Join(dir, entry.Name()),
+			})
+		}
+	}
+	return plugins
+}
+
+func pluginName(entry os.DirEntry) (string, bool) {
+	if entry.IsDir() || !strings.HasPrefix(entry.Name(), ExecutablePrefix) {
+		return "", false
+	}
+	info, err := entry.Info()
+	if err != nil || info.Mode()&0111 == 0 {
+		return "", false
+	}
+	name := strings.TrimSuffix(strings.TrimPrefix(entry.Name(), ExecutablePrefix), ".exe")
+	return name, name != ""
+}
@@ -0,0 +1,161 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/f0rt/golangAnnotations/generator/generationUtil"
+	"github.com/f0rt/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/structs.txt"))
+	os.Remove(generationUtil.Prefixed("./testData/options.txt"))
+	os.Remove("./testData")
+}
+
+// TestMain lets the test-binary act as the plugin that the tests below start
+func TestMain(m *testing.M) {
+	if os.Getenv("GOLANG_ANNOTATIONS_TEST_PLUGIN") == "1" {
+		runTestPlugin()
+	}
+	os.Exit(m.Run())
+}
+
+func runTestPlugin() {
+	Main(func(req Request) (Response, error) {
+		if req.Options["fail"] == "true" {
+			return Response{}, fmt.Errorf("failing on request")
+		}
+		if req.Options["escape"] == "true" {
+			return Response{Files: []File{{Name: "../gen_escaped.txt", Content: "escaped"}}}, nil
+		}
+		if req.Options["diagnose"] == "error" {
+			return Response{
+				Files:       []File{{Name: "gen_structs.txt", Content: "partial"}},
+				Diagnostics: []Diagnostic{{Severity: SeverityError, Message: "invalid annotation", File: "a.go"}},
+			}, nil
+		}
+		names := []string{}
+		for _, s := range req.ParsedSources.Structs {
+			names = append(names, s.Name)
+		}
+		return Response{
+			Files: []File{
+				{Name: "gen_structs.txt", Content: strings.Join(names, ",")},
+				{Name: "options.txt", Content: req.Options["greeting"]},
+			},
+			Diagnostics: []Diagnostic{
+				{Severity: SeverityInfo, Message: "done"},
+			},
+		}, nil
+	})
+}
+
+func newTestGenerator(options map[string]string) *Generator {
+	os.Setenv("GOLANG_ANNOTATIONS_TEST_PLUGIN", "1")
+	return NewGenerator("test", os.Args[0], options).(*Generator)
+}
+
+func TestGenerateWithPlugin(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{PackageName: "testData", Name: "MyStruct"},
+		{PackageName: "testData", Name: "OtherStruct"},
+	}
+	err := newTestGenerator(map[string]string{"greeting": "hello"}).Generate("testData", model.ParsedSources{Structs: s}, generationUtil.NewFileOutput())
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/structs.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "MyStruct,OtherStruct", string(data))
+
+	// prefix is enforced
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/options.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+}
+
+func TestGenerateWithFailingPlugin(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	err := newTestGenerator(map[string]string{"fail": "true"}).Generate("testData", model.ParsedSources{}, generationUtil.NewFileOutput())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failing on request")
+}
+
+func TestServeRejectsOtherProtocolVersion(t *testing.T) {
+	in, _ := json.Marshal(Request{ProtocolVersion: ProtocolVersion + 1})
+	err := serve(bytes.NewReader(in), ioutil.Discard, func(req Request) (Response, error) {
+		return Response{}, nil
+	})
+	assert.Error(t, err)
+}
+
+func TestTargetFilename(t *testing.T) {
+	target, err := targetFilename("a/b", "sub/gen_x.go")
+	assert.NoError(t, err)
+	assert.Equal(t, "a/b/sub/gen_x.go", target)
+
+	target, err = targetFilename("a/b", "sub/../x.go")
+	assert.NoError(t, err)
+	assert.Equal(t, "a/b/gen_x.go", target)
+
+	for _, name := range []string{"../other/x.go", "sub/../../x.go", "..", ".", "/etc/passwd"} {
+		_, err = targetFilename("a/b", name)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestGenerateWithEscapingPlugin(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	err := newTestGenerator(map[string]string{"escape": "true"}).Generate("testData", model.ParsedSources{}, generationUtil.NewFileOutput())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "must be within the input-dir")
+	_, err = os.Stat(generationUtil.Prefixed("./escaped.txt"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGenerateWithPluginReportingErrors(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	err := newTestGenerator(map[string]string{"diagnose": "error"}).Generate("testData", model.ParsedSources{}, generationUtil.NewFileOutput())
+	assert.Error(t, err)
+	assert.Equal(t, "Plugin test reported errors: a.go: error: invalid annotation", err.Error())
+	_, err = os.Stat(generationUtil.Prefixed("./testData/structs.txt"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDiscover(t *testing.T) {
+	dir1, err := ioutil.TempDir("", "plugins")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir1)
+	dir2, err := ioutil.TempDir("", "plugins")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir2)
+
+	ioutil.WriteFile(filepath.Join(dir1, ExecutablePrefix+"company"), []byte{}, 0755)
+	ioutil.WriteFile(filepath.Join(dir1, ExecutablePrefix+"notExecutable"), []byte{}, 0644)
+	ioutil.WriteFile(filepath.Join(dir1, "otherTool"), []byte{}, 0755)
+	ioutil.WriteFile(filepath.Join(dir2, ExecutablePrefix+"company"), []byte{}, 0755)
+	ioutil.WriteFile(filepath.Join(dir2, ExecutablePrefix+"other"), []byte{}, 0755)
+
+	plugins := discoverIn([]string{dir1, dir2})
+	assert.Equal(t, []Plugin{
+		{Name: "company", Executable: filepath.Join(dir1, ExecutablePrefix+"company")},
+		{Name: "other", Executable: filepath.Join(dir2, ExecutablePrefix+"other")},
+	}, plugins)
+}
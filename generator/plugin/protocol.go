@@ -0,0 +1,94 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/f0rt/golangAnnotations/model"
+)
+
+const (
+	// ExecutablePrefix is the prefix of every executable that is recognized as a generator plugin
+	ExecutablePrefix = "golangAnnotations-gen-"
+
+	// ProtocolVersion is incremented on every incompatible change of Request or Response
+	ProtocolVersion = 1
+)
+
+// Request is written as json to the stdin of a plugin
+type Request struct {
+	ProtocolVersion int                 `json:"protocolVersion"`
+	InputDir        string              `json:"inputDir"`
+	Options         map[string]string   `json:"options,omitempty"`
+	ParsedSources   model.ParsedSources `json:"parsedSources"`
+}
+
+// Response is read as json from the stdout of a plugin
+type Response struct {
+	Files       []File       `json:"files,omitempty"`
+	Diagnostics []Diagnostic `json:"diagnostics,omitempty"`
+	Error       string       `json:"error,omitempty"`
+}
+
+// File describes a file to be written; Name is relative to the input-dir and may not leave it
+type File struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+type Severity string
+
+const (
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// Diagnostic is a message from a plugin that is reported to the user; a diagnostic with SeverityError fails the run
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	File     string   `json:"file,omitempty"`
+}
+
+func (d Diagnostic) String() string {
+	if d.File != "" {
+		return fmt.Sprintf("%s: %s: %s", d.File, d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s: %s", d.Severity, d.Message)
+}
+
+// Main is the entry-point for plugin authors: it reads the request from stdin, calls the handler and
+// writes the response to stdout.
+func Main(handler func(req Request) (Response, error)) {
+	err := serve(os.Stdin, os.Stdout, handler)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func serve(in io.Reader, out io.Writer, handler func(req Request) (Response, error)) error {
+	var req Request
+	err := json.NewDecoder(in).Decode(&req)
+	if err != nil {
+		return fmt.Errorf("Error decoding plugin request: %s", err)
+	}
+	if req.ProtocolVersion != ProtocolVersion {
+		return fmt.Errorf("Unsupported plugin protocol version %d (expected %d)", req.ProtocolVersion, ProtocolVersion)
+	}
+
+	resp, err := handler(req)
+	if err != nil {
+		resp.Error = err.Error()
+	}
+
+	err = json.NewEncoder(out).Encode(resp)
+	if err != nil {
+		return fmt.Errorf("Error encoding plugin response: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package generatorsAnnotation
+
+import "github.com/f0rt/golangAnnotations/generator/annotation"
+
+const (
+	TypeGenerators = "Generators"
+	ParamInclude   = "include"
+	ParamSkip      = "skip"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeGenerators,
+			ParamNames: []string{ParamInclude, ParamSkip},
+			Validator:  validateGeneratorsAnnotation,
+		},
+	}
+}
+
+func validateGeneratorsAnnotation(annot annotation.Annotation) bool {
+	return annot.Name == TypeGenerators
+}
@@ -0,0 +1,155 @@
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/f0rt/golangAnnotations/generator"
+	"github.com/f0rt/golangAnnotations/generator/annotation"
+	"github.com/f0rt/golangAnnotations/generator/ast"
+	"github.com/f0rt/golangAnnotations/generator/event"
+	"github.com/f0rt/golangAnnotations/generator/eventService"
+	"github.com/f0rt/golangAnnotations/generator/jsonHelpers"
+	"github.com/f0rt/golangAnnotations/generator/openapi"
+	"github.com/f0rt/golangAnnotations/generator/plugin"
+	"github.com/f0rt/golangAnnotations/generator/registry/generatorsAnnotation"
+	"github.com/f0rt/golangAnnotations/generator/repository"
+	"github.com/f0rt/golangAnnotations/generator/rest"
+	"github.com/f0rt/golangAnnotations/generator/validator"
+)
+
+// Entry is a generator with the name that is used to select it
+type Entry struct {
+	Name      string
+	Generator generator.Generator
+}
+
+// Builtin returns the built-in generators in their default order of execution
+func Builtin() []Entry {
+	return []Entry{
+		{Name: "ast", Generator: ast.NewGenerator("ast.json")},
+		{Name: "json-helpers", Generator: jsonHelpers.NewGenerator()},
+		{Name: "validator", Generator: validator.NewGenerator()},
+		{Name: "event", Generator: event.NewGenerator()},
+		{Name: "event-service", Generator: eventService.NewGenerator()},
+		{Name: "repository", Generator: repository.NewGenerator()},
+		{Name: "rest", Generator: rest.NewGenerator()},
+		{Name: "openapi", Generator: openapi.NewGenerator()},
+	}
+}
+
+// WithPlugins adds the discovered plugins, sorted by name, after the given generators.
+// A plugin with the same name as an existing generator is ignored.
+func WithPlugins(entries []Entry, plugins []plugin.Plugin, options map[string]map[string]string) ([]Entry, []string) {
+	sorted := make([]plugin.Plugin, len(plugins))
+	copy(sorted, plugins)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	warnings := make([]string, 0)
+	for _, p := range sorted {
+		if contains(names(entries), p.Name) {
+			warnings = append(warnings, fmt.Sprintf("Ignoring plugin %s: conflicts with generator %s", p.Executable, p.Name))
+			continue
+		}
+		entries = append(entries, Entry{
+			Name:      p.Name,
+			Generator: plugin.NewGenerator(p.Name, p.Executable, options[p.Name]),
+		})
+	}
+	return entries, warnings
+}
+
+// Selection describes which generators should run: all when Include is empty, in the order of Include otherwise
+type Selection struct {
+	Include []string
+	Skip    []string
+}
+
+// NewSelection creates a selection out of comma separated lists of generator-names
+func NewSelection(include string, skip string) Selection {
+	return Selection{
+		Include: splitNames(include),
+		Skip:    splitNames(skip),
+	}
+}
+
+// Override applies a '@Generators( include = "...", skip = "..." )' annotation in the package documentation:
+// an include-list replaces the selected generators, a skip-list adds to the skipped generators.
+func (s Selection) Override(packageDocLines []string) Selection {
+	annotations := annotation.NewRegistry(generatorsAnnotation.Get())
+	ann, ok := annotations.ResolveAnnotationByName(packageDocLines, generatorsAnnotation.TypeGenerators)
+	if !ok {
+		return s
+	}
+	overridden := Selection{
+		Include: s.Include,
+		Skip:    s.Skip,
+	}
+	if include, ok := ann.Attributes[generatorsAnnotation.ParamInclude]; ok {
+		overridden.Include = splitNames(include)
+	}
+	if skip, ok := ann.Attributes[generatorsAnnotation.ParamSkip]; ok {
+		overridden.Skip = append(append([]string{}, s.Skip...), splitNames(skip)...)
+	}
+	return overridden
+}
+
+// Select returns the selected generators in a deterministic order
+func Select(available []Entry, s Selection) ([]Entry, error) {
+	for _, name := range append(append([]string{}, s.Include...), s.Skip...) {
+		if !contains(names(available), name) {
+			return nil, fmt.Errorf("Unknown generator '%s' (available: %s)", name, strings.Join(names(available), ","))
+		}
+	}
+
+	ordered := available
+	if len(s.Include) > 0 {
+		ordered = make([]Entry, 0, len(s.Include))
+		for _, name := range s.Include {
+			for _, e := range available {
+				if e.Name == name && !contains(names(ordered), name) {
+					ordered = append(ordered, e)
+				}
+			}
+		}
+	}
+
+	selected := make([]Entry, 0, len(ordered))
+	for _, e := range ordered {
+		if !contains(s.Skip, e.Name) {
+			selected = append(selected, e)
+		}
+	}
+	return selected, nil
+}
+
+func splitNames(list string) []string {
+	result := make([]string, 0)
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+func names(entries []Entry) []string {
+	result := make([]string, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, e.Name)
+	}
+	return result
+}
+
+func contains(list []string, name string) bool {
+	for _, l := range list {
+		if l == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/f0rt/golangAnnotations/generator/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func selectedNames(t *testing.T, s Selection) []string {
+	selected, err := Select(Builtin(), s)
+	assert.NoError(t, err)
+	return names(selected)
+}
+
+func TestSelectAllByDefault(t *testing.T) {
+	assert.Equal(t, []string{"ast", "json-helpers", "validator", "event", "event-service", "repository", "rest", "openapi"}, selectedNames(t, NewSelection("", "")))
+}
+
+func TestSelectInGivenOrder(t *testing.T) {
+	assert.Equal(t, []string{"rest", "event", "json-helpers"}, selectedNames(t, NewSelection("rest, event,json-helpers", "")))
+}
+
+func TestSelectWithSkip(t *testing.T) {
+	assert.Equal(t, []string{"json-helpers", "validator", "event", "event-service", "repository", "rest", "openapi"}, selectedNames(t, NewSelection("", "ast")))
+	assert.Equal(t, []string{"rest"}, selectedNames(t, NewSelection("rest,event", "event")))
+}
+
+func TestSelectUnknown(t *testing.T) {
+	_, err := Select(Builtin(), NewSelection("rest,unknown", ""))
+	assert.Error(t, err)
+	_, err = Select(Builtin(), NewSelection("", "unknown"))
+	assert.Error(t, err)
+}
+
+func TestOverrideForPackage(t *testing.T) {
+	s := NewSelection("rest,event", "ast")
+
+	assert.Equal(t, s, s.Override([]string{"// Package without annotation"}))
+
+	overridden := s.Override([]string{`// @Generators( include = "json-helpers,ast" )`})
+	assert.Equal(t, []string{"json-helpers"}, selectedNames(t, overridden))
+
+	overridden = s.Override([]string{`// @Generators( skip = "event" )`})
+	assert.Equal(t, []string{"rest"}, selectedNames(t, overridden))
+	assert.Equal(t, []string{"ast"}, s.Skip)
+}
+
+func TestWithPlugins(t *testing.T) {
+	entries, warnings := WithPlugins(Builtin(), []plugin.Plugin{
+		{Name: "zebra", Executable: "/bin/golangAnnotations-gen-zebra"},
+		{Name: "rest", Executable: "/bin/golangAnnotations-gen-rest"},
+		{Name: "company", Executable: "/bin/golangAnnotations-gen-company"},
+	}, nil)
+	assert.Equal(t, []string{"ast", "json-helpers", "validator", "event", "event-service", "repository", "rest", "openapi", "company", "zebra"}, names(entries))
+	assert.Len(t, warnings, 1)
+}
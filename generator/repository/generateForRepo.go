@@ -2,7 +2,6 @@ package repository
 
 import (
 	"fmt"
-	"log"
 	"strings"
 	"text/template"
 	"unicode"
@@ -25,7 +24,13 @@ func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
 	return repositoryAnnotation.Get()
 }
 
-func (eg *Generator) Generate(inputDir string, parsedSource model.ParsedSources) error {
+func (eg *Generator) GetTemplates() map[string]string {
+	return map[string]string{
+		"repository": repositoryTemplate,
+	}
+}
+
+func (eg *Generator) Generate(inputDir string, parsedSource model.ParsedSources, output generator.Output) error {
 	structs := parsedSource.Structs
 
 	packageName, err := generationUtil.GetPackageNameForStructs(structs)
@@ -45,10 +50,10 @@ func (eg *Generator) Generate(inputDir string, parsedSource model.ParsedSources)
 				TemplateString: repositoryTemplate,
 				FuncMap:        customTemplateFuncs,
 				Data:           repository,
+				Output:         output,
 			})
 			if err != nil {
-				log.Fatalf("Error generating repository %s: %s", repository.Name, err)
-				return err
+				return fmt.Errorf("Error generating repository %s: %w", repository.Name, err)
 			}
 		}
 	}
@@ -27,7 +27,7 @@ func TestGenerateForRepo(t *testing.T) {
 		},
 	}
 
-	err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s})
+	err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s}, generationUtil.NewFileOutput())
 	assert.Nil(t, err)
 
 	// check that generated files exisst
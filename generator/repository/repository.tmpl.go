@@ -1,14 +1,14 @@
 package repository
 
-const repositoryTemplate = `// Generated automatically by golangAnnotations: do not edit manually
-
-package {{.PackageName}}
+const repositoryTemplate = `package {{.PackageName}}
 
+{{block "imports" .}}
 import (
 	"context"
 
-	"cloud.google.com/go/datastore"
+	{{RuntimeImports "datastore" "envelope" "errorh" "eventMetaData" "request"}}
 )
+{{end}}
 
 {{if HasMethodFind . -}}
 var Find{{UpperModelName .}}OnUID = DefaultFind{{UpperModelName .}}OnUID
@@ -0,0 +1,310 @@
+package rest
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/f0rt/golangAnnotations/generator/jsonHelpers"
+	"github.com/f0rt/golangAnnotations/generator/validator"
+	"github.com/f0rt/golangAnnotations/model"
+)
+
+// paramExtractors are the httpparser-functions that extract a parameter of a basic type from a request
+var paramExtractors = map[string]string{
+	"string":        "ExtractString",
+	"bool":          "ExtractBool",
+	"int":           "ExtractNumber",
+	"int64":         "ExtractInt64",
+	"uint":          "ExtractUint",
+	"float64":       "ExtractFloat",
+	"time.Time":     "ExtractTime",
+	"time.Duration": "ExtractDuration",
+	"mydate.MyDate": "ExtractDate",
+	"[]string":      "ExtractStringSlice",
+	"[]int":         "ExtractNumberSlice",
+	"[]bool":        "ExtractBoolSlice",
+}
+
+// ArgTypes classifies the arguments of the operations of a package. An argument of a basic type or of one of the
+// json-enums of the package is a parameter of the request, that is read from its path or query. A query-struct of
+// the package, a struct with query-tagged fields, binds the query of a GET or DELETE operation. An argument of
+// another custom type is the body of the request; its fields are validated when it has a generated Validate-method.
+type ArgTypes struct {
+	enums        map[string]bool
+	queryStructs map[string][]QueryField
+	validated    map[string]bool
+}
+
+// QueryField is a field of a query-struct, tagged like `query:"name,required"` or `query:"limit,default=10"`
+type QueryField struct {
+	Field    model.Field
+	Param    string
+	Required bool
+	Default  string
+}
+
+func NewArgTypes(structs []model.Struct, enums []model.Enum) ArgTypes {
+	argTypes := ArgTypes{enums: map[string]bool{}, queryStructs: map[string][]QueryField{}, validated: validator.GetValidatedStructs(structs)}
+	for _, e := range enums {
+		if jsonHelpers.IsJSONEnum(e) {
+			argTypes.enums[e.Name] = true
+		}
+	}
+	for _, s := range structs {
+		if fields := getQueryFields(s); len(fields) > 0 {
+			argTypes.queryStructs[s.Name] = fields
+		}
+	}
+	return argTypes
+}
+
+func getQueryFields(s model.Struct) []QueryField {
+	fields := []QueryField{}
+	for _, f := range s.Fields {
+		tag, ok := f.GetTagMap()["query"]
+		if !ok || tag == "-" {
+			continue
+		}
+		// the default-value comes last, so that it can hold the comma-separated values of a slice
+		tag, defaultValue, _ := strings.Cut(tag, ",default=")
+		parts := strings.Split(tag, ",")
+		field := QueryField{Field: f, Param: parts[0], Default: defaultValue}
+		if field.Param == "" {
+			field.Param = f.Name
+		}
+		for _, option := range parts[1:] {
+			if option == "required" {
+				field.Required = true
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// IsEnumArg tells if the argument is a json-enum that is passed by name
+func (at ArgTypes) IsEnumArg(f model.Field) bool {
+	return at.enums[f.TypeName]
+}
+
+func (at ArgTypes) IsParamArg(f model.Field) bool {
+	_, ok := paramExtractors[f.TypeName]
+	return ok || at.IsEnumArg(f)
+}
+
+// IsQueryStructArg tells if the argument is a query-struct that binds the query of a GET or DELETE operation
+func (at ArgTypes) IsQueryStructArg(o model.Operation, arg model.Field) bool {
+	_, ok := at.queryStructs[arg.DereferencedTypeName()]
+	return ok && !IsClientBodyMethod(o)
+}
+
+func (at ArgTypes) GetQueryFields(arg model.Field) []QueryField {
+	return at.queryStructs[arg.DereferencedTypeName()]
+}
+
+func (at ArgTypes) validateQueryStructs(s model.Struct) error {
+	for _, o := range s.Operations {
+		for _, arg := range o.InputArgs {
+			if !at.IsQueryStructArg(*o, arg) {
+				continue
+			}
+			for _, field := range at.GetQueryFields(arg) {
+				if !at.IsParamArg(field.Field) {
+					return fmt.Errorf("Unsupported type %s of query-parameter %s in %s", field.Field.TypeName, field.Param, arg.DereferencedTypeName())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (at ArgTypes) IsCustomArg(f model.Field) bool {
+	return f.IsCustom() && !at.IsParamArg(f)
+}
+
+func (at ArgTypes) IsInputArg(arg model.Field) bool {
+	return at.IsCustomArg(arg) && !IsContextArg(arg) && !IsRequestContextArg(arg)
+}
+
+func (at ArgTypes) HasInput(o model.Operation) bool {
+	if GetRestOperationMethod(o) == "POST" || GetRestOperationMethod(o) == "PUT" {
+		for _, arg := range o.InputArgs {
+			if at.IsInputArg(arg) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (at ArgTypes) GetInputArgType(o model.Operation) string {
+	for _, arg := range o.InputArgs {
+		if at.IsInputArg(arg) {
+			return arg.DereferencedTypeName()
+		}
+	}
+	return ""
+}
+
+func (at ArgTypes) GetInputArgName(o model.Operation) string {
+	for _, arg := range o.InputArgs {
+		if at.IsInputArg(arg) {
+			return arg.Name
+		}
+	}
+	return ""
+}
+
+// HasValidatedInput tells if the body of the request has a Validate-method, generated from its validate-tags
+func (at ArgTypes) HasValidatedInput(o model.Operation) bool {
+	return at.HasInput(o) && !HasUpload(o) && at.validated[at.GetInputArgType(o)]
+}
+
+// RequiresParamValidation tells if the operation has parameters or a validated body: their field-errors are collected
+func (at ArgTypes) RequiresParamValidation(o model.Operation) bool {
+	if at.HasValidatedInput(o) {
+		return true
+	}
+	for _, arg := range o.InputArgs {
+		if at.IsParamArg(arg) || at.IsQueryStructArg(o, arg) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetExtractFunction returns the httpparser-function that extracts the parameter from a request
+func (at ArgTypes) GetExtractFunction(f model.Field) string {
+	if at.IsEnumArg(f) {
+		return fmt.Sprintf("ExtractEnum[%s]", f.TypeName)
+	}
+	return paramExtractors[f.TypeName]
+}
+
+func (at ArgTypes) isClientParam(o model.Operation, arg model.Field) bool {
+	if IsContextArg(arg) || IsRequestContextArg(arg) {
+		return false
+	}
+	return !at.IsCustomArg(arg) || at.IsQueryStructArg(o, arg) || (at.IsInputArg(arg) && at.HasInput(o))
+}
+
+// IsURLParam tells if the argument is a path- or query-parameter, or a query-struct, in the url of the operation
+func (at ArgTypes) IsURLParam(o model.Operation, arg model.Field) bool {
+	if IsHeaderParam(o, arg) || IsCookieParam(o, arg) {
+		return false
+	}
+	return at.isClientParam(o, arg) && (!at.IsCustomArg(arg) || at.IsQueryStructArg(o, arg))
+}
+
+func (at ArgTypes) IsClientQueryParam(o model.Operation, arg model.Field) bool {
+	return at.IsURLParam(o, arg) && !IsPathParam(o, arg)
+}
+
+func (at ArgTypes) GetClientParams(o model.Operation) string {
+	params := []string{"c context.Context"}
+	for _, arg := range o.InputArgs {
+		if at.isClientParam(o, arg) {
+			params = append(params, fmt.Sprintf("%s %s", arg.Name, arg.TypeName))
+		}
+	}
+	return strings.Join(params, ", ")
+}
+
+// GetURLParams returns the declaration of the path- and query-parameters of the operation
+func (at ArgTypes) GetURLParams(o model.Operation) string {
+	params := []string{}
+	for _, arg := range o.InputArgs {
+		if at.IsURLParam(o, arg) {
+			params = append(params, fmt.Sprintf("%s %s", arg.Name, arg.TypeName))
+		}
+	}
+	return strings.Join(params, ", ")
+}
+
+// GetQueryEncoding returns the statements that add a query-parameter, or the fields of a query-struct, to the
+// url.Values named query. Zero-valued fields of a query-struct are left out, so that their defaults apply.
+func (at ArgTypes) GetQueryEncoding(o model.Operation, arg model.Field) []string {
+	if !at.IsQueryStructArg(o, arg) {
+		return encodeQueryParam(GetParamName(o, arg), arg.TypeName, arg.Name, false)
+	}
+	lines := []string{}
+	if arg.IsPointer() {
+		lines = append(lines, fmt.Sprintf("if %s != nil {", arg.Name))
+	}
+	for _, field := range at.GetQueryFields(arg) {
+		lines = append(lines, encodeQueryParam(field.Param, field.Field.TypeName, arg.Name+"."+field.Field.Name, true)...)
+	}
+	if arg.IsPointer() {
+		lines = append(lines, "}")
+	}
+	return lines
+}
+
+func encodeQueryParam(name string, typeName string, value string, omitZero bool) []string {
+	if strings.HasPrefix(typeName, "[]") {
+		return []string{
+			fmt.Sprintf("for _, value := range %s {", value),
+			fmt.Sprintf("query.Add(%q, %s)", name, FormatParam(typeName, "value")),
+			"}",
+		}
+	}
+	set := fmt.Sprintf("query.Set(%q, %s)", name, FormatParam(typeName, value))
+	if !omitZero {
+		return []string{set}
+	}
+	return []string{fmt.Sprintf("if %s {", nonZeroCondition(typeName, value)), set, "}"}
+}
+
+func nonZeroCondition(typeName string, value string) string {
+	switch typeName {
+	case "string":
+		return fmt.Sprintf("%s != \"\"", value)
+	case "bool":
+		return value
+	case "time.Time":
+		return fmt.Sprintf("!%s.IsZero()", value)
+	case "mydate.MyDate":
+		return fmt.Sprintf("%s != (mydate.MyDate{})", value)
+	default:
+		return fmt.Sprintf("%s != 0", value)
+	}
+}
+
+// FormatParam returns the expression that formats a parameter-value the way that httpparser parses it
+func FormatParam(typeName string, value string) string {
+	switch strings.TrimPrefix(typeName, "[]") {
+	case "time.Time":
+		return fmt.Sprintf("%s.Format(time.RFC3339Nano)", value)
+	case "string":
+		return value
+	default:
+		return fmt.Sprintf("fmt.Sprint(%s)", value)
+	}
+}
+
+// templateFuncs returns the template-functions, of which the ones that classify arguments know the json-enums
+func (at ArgTypes) templateFuncs() template.FuncMap {
+	funcs := template.FuncMap{}
+	for name, f := range customTemplateFuncs {
+		funcs[name] = f
+	}
+	funcs["IsEnumArg"] = at.IsEnumArg
+	funcs["IsParamArg"] = at.IsParamArg
+	funcs["IsCustomArg"] = at.IsCustomArg
+	funcs["IsInputArg"] = at.IsInputArg
+	funcs["HasInput"] = at.HasInput
+	funcs["GetInputArgType"] = at.GetInputArgType
+	funcs["GetInputArgName"] = at.GetInputArgName
+	funcs["HasValidatedInput"] = at.HasValidatedInput
+	funcs["RequiresParamValidation"] = at.RequiresParamValidation
+	funcs["GetExtractFunction"] = at.GetExtractFunction
+	funcs["IsURLParam"] = at.IsURLParam
+	funcs["IsClientQueryParam"] = at.IsClientQueryParam
+	funcs["GetClientParams"] = at.GetClientParams
+	funcs["GetURLParams"] = at.GetURLParams
+	funcs["IsQueryStructArg"] = at.IsQueryStructArg
+	funcs["GetQueryFields"] = at.GetQueryFields
+	funcs["GetQueryEncoding"] = at.GetQueryEncoding
+	return funcs
+}
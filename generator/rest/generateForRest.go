@@ -2,12 +2,12 @@ package rest
 
 import (
 	"fmt"
-	"log"
 	"regexp"
 	"strings"
 	"text/template"
 	"unicode"
 
+	"github.com/f0rt/golangAnnotations/config"
 	"github.com/f0rt/golangAnnotations/generator"
 	"github.com/f0rt/golangAnnotations/generator/annotation"
 	"github.com/f0rt/golangAnnotations/generator/generationUtil"
@@ -26,39 +26,81 @@ func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
 	return restAnnotation.Get()
 }
 
-func (eg *Generator) Generate(inputDir string, parsedSource model.ParsedSources) error {
-	return generate(inputDir, parsedSource.Structs)
+func (eg *Generator) GetTemplates() map[string]string {
+	return map[string]string{
+		"http-handlers":     httpHandlersTemplate,
+		"http-client":       httpClientTemplate,
+		"http-test-helpers": testHelpersTemplate,
+		"testService":       testServiceTemplate,
+	}
+}
+
+func (eg *Generator) Generate(inputDir string, parsedSource model.ParsedSources, output generator.Output) error {
+	return generate(inputDir, parsedSource.Structs, NewArgTypes(parsedSource.Structs, parsedSource.Enums), output)
 }
 
 type generateContext struct {
-	targetDir   string
+	target      generationUtil.Target
 	packageName string
 	service     model.Struct
+	funcs       template.FuncMap
+	output      generator.Output
 }
 
-func generate(inputDir string, structs []model.Struct) error {
+func generate(inputDir string, structs []model.Struct, argTypes ArgTypes, output generator.Output) error {
 
 	packageName, err := generationUtil.GetPackageNameForStructs(structs)
 	if packageName == "" || err != nil {
 		return err
 	}
-	targetDir, err := generationUtil.DetermineTargetPath(inputDir, packageName)
+	target, err := generationUtil.DetermineTarget(inputDir, packageName)
 	if err != nil {
 		return err
 	}
 
 	for _, service := range structs {
 		if IsRestService(service) {
+			err = validateRouter(service)
+			if err != nil {
+				return err
+			}
+			err = validateMiddleware(service)
+			if err != nil {
+				return err
+			}
+			err = argTypes.validateQueryStructs(service)
+			if err != nil {
+				return err
+			}
+			err = argTypes.validateParamBindings(service)
+			if err != nil {
+				return err
+			}
+			err = validateStatus(service)
+			if err != nil {
+				return err
+			}
+			err = validateFormats(service)
+			if err != nil {
+				return err
+			}
 			ctx := generateContext{
-				targetDir:   targetDir,
+				target:      target,
 				packageName: packageName,
 				service:     service,
+				funcs:       argTypes.templateFuncs(),
+				output:      output,
 			}
 			err = generateHTTPService(ctx)
 			if err != nil {
 				return err
 			}
 
+			err = generateHTTPClient(ctx)
+			if err != nil {
+				return err
+			}
+
 			if !IsRestServiceNoTest(service) {
 				err = generateHTTPTestHelpers(ctx)
 				if err != nil {
@@ -77,15 +119,31 @@ func generate(inputDir string, structs []model.Struct) error {
 func generateHTTPService(ctx generateContext) error {
 	err := generationUtil.Generate(generationUtil.Info{
 		Src:            fmt.Sprintf("%s.%s", ctx.service.PackageName, ToFirstUpper(ctx.service.Name)),
-		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/http%s.go", ctx.targetDir, ToFirstUpper(ctx.service.Name))),
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/http%s.go", ctx.target.Dir, ToFirstUpper(ctx.service.Name))),
 		TemplateName:   "http-handlers",
 		TemplateString: httpHandlersTemplate,
-		FuncMap:        customTemplateFuncs,
+		FuncMap:        ctx.funcs,
 		Data:           ctx.service,
+		Output:         ctx.output,
 	})
 	if err != nil {
-		log.Fatalf("Error generating handlers for service %s: %s", ctx.service.Name, err)
-		return err
+		return fmt.Errorf("Error generating handlers for service %s: %w", ctx.service.Name, err)
+	}
+	return nil
+}
+
+func generateHTTPClient(ctx generateContext) error {
+	err := generationUtil.Generate(generationUtil.Info{
+		Src:            fmt.Sprintf("%s.%s", ctx.service.PackageName, ToFirstUpper(ctx.service.Name)),
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/http%sClient.go", ctx.target.Dir, ToFirstUpper(ctx.service.Name))),
+		TemplateName:   "http-client",
+		TemplateString: httpClientTemplate,
+		FuncMap:        ctx.funcs,
+		Data:           ctx.service,
+		Output:         ctx.output,
+	})
+	if err != nil {
+		return fmt.Errorf("Error generating client for service %s: %w", ctx.service.Name, err)
 	}
 	return nil
 }
@@ -93,36 +151,35 @@ func generateHTTPService(ctx generateContext) error {
 func generateHTTPTestHelpers(ctx generateContext) error {
 	err := generationUtil.Generate(generationUtil.Info{
 		Src:            fmt.Sprintf("%s.%s", ctx.service.PackageName, ToFirstUpper(ctx.service.Name)),
-		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/http%sHelpers_test.go", ctx.targetDir, ToFirstUpper(ctx.service.Name))),
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/http%sHelpers_test.go", ctx.target.Dir, ToFirstUpper(ctx.service.Name))),
 		TemplateName:   "http-test-helpers",
 		TemplateString: testHelpersTemplate,
-		FuncMap:        customTemplateFuncs,
+		FuncMap:        ctx.funcs,
 		Data:           ctx.service,
+		Output:         ctx.output,
 	})
 	if err != nil {
-		log.Fatalf("Error generating helpers for service %s: %s", ctx.service.Name, err)
-		return err
+		return fmt.Errorf("Error generating helpers for service %s: %w", ctx.service.Name, err)
 	}
 	return nil
 }
 
 func generateHTTPTestService(ctx generateContext) error {
-	// create this file within a subdirectoty
-	ctx.packageName = ctx.packageName + "TestLog"
+	// create this file within a subdirectory
+	sub := ctx.target.Sub(generationUtil.GetConfig().Output.TestLogSuffix)
 
-	ctx.service.PackageName = ctx.packageName
-	target := generationUtil.Prefixed(fmt.Sprintf("%s/%s/httpTest%s.go", ctx.targetDir, ctx.packageName, ToFirstUpper(ctx.service.Name)))
+	ctx.service.PackageName = sub.PackageName
 	err := generationUtil.Generate(generationUtil.Info{
 		Src:            fmt.Sprintf("%s.%s", ctx.service.PackageName, ToFirstUpper(ctx.service.Name)),
-		TargetFilename: target,
+		TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/httpTest%s.go", sub.Dir, ToFirstUpper(ctx.service.Name))),
 		TemplateName:   "testService",
 		TemplateString: testServiceTemplate,
-		FuncMap:        customTemplateFuncs,
+		FuncMap:        ctx.funcs,
 		Data:           ctx.service,
+		Output:         ctx.output,
 	})
 	if err != nil {
-		log.Fatalf("Error generating testHandler for service %s: %s", ctx.service.Name, err)
-		return err
+		return fmt.Errorf("Error generating testHandler for service %s: %w", ctx.service.Name, err)
 	}
 	return nil
 }
@@ -131,6 +188,10 @@ var customTemplateFuncs = template.FuncMap{
 	"IsRestService":                         IsRestService,
 	"ExtractImports":                        ExtractImports,
 	"GetRestServicePath":                    GetRestServicePath,
+	"IsRestServiceServeMux":                 IsRestServiceServeMux,
+	"GetServeMuxPattern":                    GetServeMuxPattern,
+	"GetServeMuxSlashPattern":               GetServeMuxSlashPattern,
+	"GetRestOperationHandler":               GetRestOperationHandler,
 	"GetExtractRequestContextMethod":        GetExtractRequestContextMethod,
 	"DoesRestServiceRequireRoleValidation":  DoesRestServiceRequireRoleValidation,
 	"IsRestOperation":                       IsRestOperation,
@@ -142,7 +203,16 @@ var customTemplateFuncs = template.FuncMap{
 	"IsRestOperationTransactional":          IsRestOperationTransactional,
 	"IsRestOperationForm":                   IsRestOperationForm,
 	"IsRestOperationJSON":                   IsRestOperationJSON,
+	"IsRestOperationXML":                    IsRestOperationXML,
 	"IsRestOperationHTML":                   IsRestOperationHTML,
+	"IsRestOperationNegotiated":             IsRestOperationNegotiated,
+	"GetRestOperationFormat":                GetRestOperationFormat,
+	"GetRestOperationFormats":               GetRestOperationFormats,
+	"GetRestOperationMediaTypes":            GetRestOperationMediaTypes,
+	"GetFormatContentType":                  GetFormatContentType,
+	"GetFormatMediaType":                    GetFormatMediaType,
+	"GetAcceptType":                         GetAcceptType,
+	"ForFormat":                             forFormat,
 	"IsRestOperationCSV":                    IsRestOperationCSV,
 	"IsRestOperationTXT":                    IsRestOperationTXT,
 	"IsRestOperationMD":                     IsRestOperationMD,
@@ -163,6 +233,35 @@ var customTemplateFuncs = template.FuncMap{
 	"HasAnyPathParam":                       HasAnyPathParam,
 	"IsSliceParam":                          IsSliceParam,
 	"IsQueryParam":                          IsQueryParam,
+	"GetParamName":                          GetParamName,
+	"GetClientName":                         GetClientName,
+	"IsClientOperation":                     IsClientOperation,
+	"IsClientBodyMethod":                    IsClientBodyMethod,
+	"IsClientQueryParam":                    IsClientQueryParam,
+	"IsEnumArg":                             ArgTypes{}.IsEnumArg,
+	"IsParamArg":                            ArgTypes{}.IsParamArg,
+	"IsInputArg":                            IsInputArg,
+	"GetExtractFunction":                    ArgTypes{}.GetExtractFunction,
+	"IsURLParam":                            ArgTypes{}.IsURLParam,
+	"GetURLParams":                          ArgTypes{}.GetURLParams,
+	"FormatParam":                           FormatParam,
+	"HasRestOperationStatus":                HasRestOperationStatus,
+	"GetRestOperationStatusConstant":        GetRestOperationStatusConstant,
+	"HasRestOperationLocation":              HasRestOperationLocation,
+	"GetRestOperationLocation":              GetRestOperationLocation,
+	"IsHeaderParam":                         IsHeaderParam,
+	"IsCookieParam":                         IsCookieParam,
+	"HasHeaderParams":                       HasHeaderParams,
+	"GetParamSource":                        GetParamSource,
+	"GetHeaderEncoding":                     GetHeaderEncoding,
+	"IsQueryStructArg":                      ArgTypes{}.IsQueryStructArg,
+	"GetQueryFields":                        ArgTypes{}.GetQueryFields,
+	"GetQueryEncoding":                      ArgTypes{}.GetQueryEncoding,
+	"GetClientMethodName":                   GetClientMethodName,
+	"GetClientParams":                       GetClientParams,
+	"GetClientResults":                      GetClientResults,
+	"GetClientResultType":                   GetClientResultType,
+	"GetClientPathExpression":               GetClientPathExpression,
 	"GetInputArgName":                       GetInputArgName,
 	"GetInputParamString":                   GetInputParamString,
 	"GetOutputArgType":                      GetOutputArgType,
@@ -175,6 +274,7 @@ var customTemplateFuncs = template.FuncMap{
 	"IsStringSliceArg":                      IsStringSliceArg,
 	"IsDateArg":                             IsDateArg,
 	"IsCustomArg":                           IsCustomArg,
+	"HasValidatedInput":                     ArgTypes{}.HasValidatedInput,
 	"RequiresParamValidation":               RequiresParamValidation,
 	"IsInputArgMandatory":                   IsInputArgMandatory,
 	"HasUpload":                             HasUpload,
@@ -226,6 +326,143 @@ func GetRestServicePath(s model.Struct) string {
 	return ""
 }
 
+const (
+	routerMux      = "mux"
+	routerServeMux = "servemux"
+)
+
+// GetRestServiceRouter returns the router of the service: the router of its annotation, or else the configured one
+func GetRestServiceRouter(s model.Struct) string {
+	annotations := annotation.NewRegistry(restAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, restAnnotation.TypeRestService); ok {
+		if router := ann.Attributes[restAnnotation.ParamRouter]; router != "" {
+			return router
+		}
+	}
+	if router := generationUtil.GetConfig().Option("rest", config.OptionRESTRouter); router != "" {
+		return router
+	}
+	return routerMux
+}
+
+func validateRouter(s model.Struct) error {
+	switch GetRestServiceRouter(s) {
+	case routerMux:
+	case routerServeMux:
+		for _, o := range s.Operations {
+			if IsRestOperation(*o) && strings.Contains(GetRestOperationPath(*o), ":") {
+				return fmt.Errorf("Operation %s of service %s: path %s has a regular expression, which the servemux router does not support",
+					o.Name, s.Name, GetRestOperationPath(*o))
+			}
+		}
+	default:
+		return fmt.Errorf("Unsupported router '%s' for service %s: use %s or %s", GetRestServiceRouter(s), s.Name, routerMux, routerServeMux)
+	}
+	return nil
+}
+
+func IsRestServiceServeMux(s model.Struct) bool {
+	return GetRestServiceRouter(s) == routerServeMux
+}
+
+// GetServeMuxPattern returns the net/http pattern of an operation, like "GET /api/person/{uid}". A path with a
+// trailing slash only matches itself, not the whole subtree.
+func GetServeMuxPattern(s model.Struct, o model.Operation) string {
+	path := GetRestServicePath(s) + GetRestOperationPath(o)
+	if path == "" {
+		path = "/"
+	}
+	if strings.HasSuffix(path, "/") {
+		path += "{$}"
+	}
+	return fmt.Sprintf("%s %s", GetRestOperationMethod(o), path)
+}
+
+// GetServeMuxSlashPattern returns the pattern of the path with a trailing slash added or removed: like gorilla/mux
+// with strict-slash, a request for it is redirected to the path of the operation. It is empty for the root, and when
+// another operation of the service registers the same pattern: http.ServeMux does not accept it twice.
+func GetServeMuxSlashPattern(s model.Struct, o model.Operation) string {
+	path := GetRestServicePath(s) + GetRestOperationPath(o)
+	if path == "" || path == "/" {
+		return ""
+	}
+	if strings.HasSuffix(path, "/") {
+		path = strings.TrimSuffix(path, "/")
+	} else {
+		path += "/{$}"
+	}
+	pattern := fmt.Sprintf("%s %s", GetRestOperationMethod(o), path)
+	for _, other := range s.Operations {
+		if IsRestOperation(*other) && equalServeMuxPatterns(GetServeMuxPattern(s, *other), pattern) {
+			return ""
+		}
+	}
+	return pattern
+}
+
+var serveMuxWildcard = regexp.MustCompile(`\{\w+(\.\.\.)?\}`)
+
+// equalServeMuxPatterns tells if http.ServeMux considers the patterns the same: the names of wildcards do not matter
+func equalServeMuxPatterns(a string, b string) bool {
+	return serveMuxWildcard.ReplaceAllString(a, "{$1}") == serveMuxWildcard.ReplaceAllString(b, "{$1}")
+}
+
+// GetRestServiceMiddleware returns the functions that wrap the handler of every operation of the service
+func GetRestServiceMiddleware(s model.Struct) []string {
+	annotations := annotation.NewRegistry(restAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, restAnnotation.TypeRestService); ok {
+		return splitList(ann.Attributes[restAnnotation.ParamMiddleware])
+	}
+	return []string{}
+}
+
+// GetRestOperationMiddleware returns the functions that wrap the handler of the operation, within the middleware of
+// its service
+func GetRestOperationMiddleware(o model.Operation) []string {
+	annotations := annotation.NewRegistry(restAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeRestOperation); ok {
+		return splitList(ann.Attributes[restAnnotation.ParamMiddleware])
+	}
+	return []string{}
+}
+
+var middlewareName = regexp.MustCompile(`^[A-Za-z_]\w*(\.[A-Za-z_]\w*)?$`)
+
+func validateMiddleware(s model.Struct) error {
+	names := GetRestServiceMiddleware(s)
+	for _, o := range s.Operations {
+		names = append(names, GetRestOperationMiddleware(*o)...)
+	}
+	for _, name := range names {
+		if !middlewareName.MatchString(name) {
+			return fmt.Errorf("Invalid middleware '%s' for service %s: expected the name of a func(http.HandlerFunc) http.HandlerFunc", name, s.Name)
+		}
+	}
+	return nil
+}
+
+// GetRestOperationHandler returns the expression that creates the handler of an operation, wrapped in the
+// middleware of the service and the operation: the first declared middleware sees the request first
+func GetRestOperationHandler(s model.Struct, o model.Operation) string {
+	middleware := append(GetRestServiceMiddleware(s), GetRestOperationMiddleware(o)...)
+	handler := fmt.Sprintf("%s(ts)", o.Name)
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = fmt.Sprintf("%s(%s)", middleware[i], handler)
+	}
+	return handler
+}
+
+func splitList(list string) []string {
+	result := []string{}
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
 func GetExtractRequestContextMethod(s model.Struct) string {
 	annotations := annotation.NewRegistry(restAnnotation.Get())
 	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, restAnnotation.TypeRestService); ok {
@@ -348,16 +585,33 @@ func HasAnyPathParam(o model.Operation) bool {
 	return len(getAllPathParams(o)) > 0
 }
 
+// pathVariable matches a variable of a path, like {uid}, or like {uid:[0-9]+} with the regular expression that
+// gorilla/mux matches it with
+var pathVariable = regexp.MustCompile(`\{(\w+)(?::((?:[^{}]|\{[^{}]*\})*))?\}`)
+
 func getAllPathParams(o model.Operation) []string {
-	re := regexp.MustCompile(`\{\w+\}`)
-	path := GetRestOperationPath(o)
-	params := re.FindAllString(path, -1)
-	for idx, param := range params {
-		params[idx] = param[1 : len(param)-1]
+	params := []string{}
+	for _, match := range pathVariable.FindAllStringSubmatch(GetRestOperationPath(o), -1) {
+		params = append(params, match[1])
 	}
 	return params
 }
 
+// GetPathParamPattern returns the regular expression of a path-parameter of the operation, or "" when it has none
+func GetPathParamPattern(o model.Operation, name string) string {
+	for _, match := range pathVariable.FindAllStringSubmatch(GetRestOperationPath(o), -1) {
+		if match[1] == name {
+			return match[2]
+		}
+	}
+	return ""
+}
+
+// StripPathPatterns returns the path without the regular expressions of its variables, like /person/{uid}
+func StripPathPatterns(path string) string {
+	return pathVariable.ReplaceAllString(path, "{$1}")
+}
+
 func GetRestOperationMethod(o model.Operation) string {
 	annotations := annotation.NewRegistry(restAnnotation.Get())
 	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeRestOperation); ok {
@@ -374,18 +628,37 @@ func IsRestOperationForm(o model.Operation) bool {
 	return false
 }
 
-func GetRestOperationFormat(o model.Operation) string {
+// GetRestOperationFormats returns the formats that the operation negotiates, like "JSON,XML,CSV"
+func GetRestOperationFormats(o model.Operation) []string {
 	annotations := annotation.NewRegistry(restAnnotation.Get())
 	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeRestOperation); ok {
-		return ann.Attributes[restAnnotation.ParamFormat]
+		return splitList(ann.Attributes[restAnnotation.ParamFormat])
 	}
-	return ""
+	return []string{}
+}
+
+// GetRestOperationFormat returns the first format of the operation: the one it responds in by default
+func GetRestOperationFormat(o model.Operation) string {
+	formats := GetRestOperationFormats(o)
+	if len(formats) == 0 {
+		return ""
+	}
+	return formats[0]
+}
+
+// IsRestOperationNegotiated tells if the operation responds in the format that the Accept-header prefers
+func IsRestOperationNegotiated(o model.Operation) bool {
+	return len(GetRestOperationFormats(o)) > 1
 }
 
 func IsRestOperationJSON(o model.Operation) bool {
 	return GetRestOperationFormat(o) == "JSON"
 }
 
+func IsRestOperationXML(o model.Operation) bool {
+	return GetRestOperationFormat(o) == "XML"
+}
+
 func IsRestOperationHTML(o model.Operation) bool {
 	return GetRestOperationFormat(o) == "HTML"
 }
@@ -415,20 +688,95 @@ func HasContentType(operation model.Operation) bool {
 }
 
 func GetContentType(operation model.Operation) string {
-	switch GetRestOperationFormat(operation) {
-	case "JSON":
-		return "application/json"
-	case "HTML":
-		return "text/html; charset=UTF-8"
-	case "CSV":
-		return "text/csv; charset=UTF-8"
-	case "TXT":
-		return "text/plain; charset=UTF-8"
-	case "MD":
-		return "text/markdown; charset=UTF-8"
-	default:
-		return ""
+	return GetFormatContentType(GetRestOperationFormat(operation))
+}
+
+// formatContentTypes are the content-types of the formats that can be negotiated
+var formatContentTypes = map[string]string{
+	"JSON": "application/json",
+	"XML":  "application/xml; charset=UTF-8",
+	"HTML": "text/html; charset=UTF-8",
+	"CSV":  "text/csv; charset=UTF-8",
+	"TXT":  "text/plain; charset=UTF-8",
+	"MD":   "text/markdown; charset=UTF-8",
+}
+
+func GetFormatContentType(format string) string {
+	return formatContentTypes[format]
+}
+
+// GetFormatMediaType returns the content-type of the format without its parameters
+func GetFormatMediaType(format string) string {
+	mediaType, _, _ := strings.Cut(GetFormatContentType(format), ";")
+	return mediaType
+}
+
+// GetRestOperationMediaTypes returns the quoted media-types of the formats of the operation, in order of preference
+func GetRestOperationMediaTypes(o model.Operation) string {
+	mediaTypes := []string{}
+	for _, format := range GetRestOperationFormats(o) {
+		mediaTypes = append(mediaTypes, fmt.Sprintf("%q", GetFormatMediaType(format)))
 	}
+	return strings.Join(mediaTypes, ", ")
+}
+
+// GetAcceptType returns the Accept-header with which a test requests the default format of the operation
+func GetAcceptType(o model.Operation) string {
+	if mediaType := GetFormatMediaType(GetRestOperationFormat(o)); mediaType != "" {
+		return mediaType
+	}
+	return "application/json"
+}
+
+// formatResponse is the operation whose response is written in one of its formats
+type formatResponse struct {
+	Operation model.Operation
+	Format    string
+}
+
+func forFormat(o model.Operation, format string) formatResponse {
+	return formatResponse{Operation: o, Format: format}
+}
+
+func validateFormats(s model.Struct) error {
+	for _, o := range s.Operations {
+		if !IsRestOperation(*o) {
+			continue
+		}
+		err := validateXMLResult(*o)
+		if err != nil {
+			return err
+		}
+		if !IsRestOperationNegotiated(*o) {
+			continue
+		}
+		seen := map[string]bool{}
+		for _, format := range GetRestOperationFormats(*o) {
+			if GetFormatContentType(format) == "" || seen[format] {
+				return fmt.Errorf("Unsupported format '%s' of operation %s: negotiation supports JSON, XML, HTML, CSV, TXT and MD, each once", format, o.Name)
+			}
+			seen[format] = true
+		}
+	}
+	return nil
+}
+
+// validateXMLResult rejects XML for a slice or map result: encoding/xml writes a slice as several root elements, which is
+// not a well-formed document, and fails on a map after the status has been written
+func validateXMLResult(o model.Operation) error {
+	if !HasOutput(o) {
+		return nil
+	}
+	for _, format := range GetRestOperationFormats(o) {
+		if format != "XML" {
+			continue
+		}
+		result := model.Field{TypeName: strings.TrimPrefix(GetOutputArgType(o), "*")}
+		if result.IsSlice() || result.IsMap() {
+			return fmt.Errorf("Unsupported format XML of operation %s: its result %s has no single root element, return a struct instead", o.Name, GetOutputArgType(o))
+		}
+	}
+	return nil
 }
 
 func GetRestOperationFilename(o model.Operation) string {
@@ -491,14 +839,7 @@ func asStringSlice(in []string) string {
 }
 
 func HasInput(o model.Operation) bool {
-	if GetRestOperationMethod(o) == "POST" || GetRestOperationMethod(o) == "PUT" {
-		for _, arg := range o.InputArgs {
-			if IsInputArg(arg) {
-				return true
-			}
-		}
-	}
-	return false
+	return ArgTypes{}.HasInput(o)
 }
 
 func HasRequestContext(o model.Operation) bool {
@@ -545,12 +886,7 @@ func GetContextName(o model.Operation) string {
 }
 
 func GetInputArgType(o model.Operation) string {
-	for _, arg := range o.InputArgs {
-		if IsInputArg(arg) {
-			return arg.DereferencedTypeName()
-		}
-	}
-	return ""
+	return ArgTypes{}.GetInputArgType(o)
 }
 
 func IsSliceParam(arg model.Field) bool {
@@ -569,13 +905,32 @@ func IsQueryParam(o model.Operation, arg model.Field) bool {
 	return true
 }
 
-func GetInputArgName(o model.Operation) string {
-	for _, arg := range o.InputArgs {
-		if IsInputArg(arg) {
-			return arg.Name
+func IsPathParam(o model.Operation, arg model.Field) bool {
+	for _, pathParam := range getAllPathParams(o) {
+		if pathParam == arg.Name {
+			return true
 		}
 	}
-	return ""
+	return false
+}
+
+// GetParamName returns the name under which an argument is passed: a path-parameter has the name of the
+// argument, a query-parameter its uncapitalized name
+func GetParamName(o model.Operation, arg model.Field) string {
+	if binding, ok := findParamBinding(GetRestOperationHeaders(o), arg); ok {
+		return binding.Name
+	}
+	if binding, ok := findParamBinding(GetRestOperationCookies(o), arg); ok {
+		return binding.Name
+	}
+	if IsPathParam(o, arg) {
+		return arg.Name
+	}
+	return Uncapitalized(arg.Name)
+}
+
+func GetInputArgName(o model.Operation) string {
+	return ArgTypes{}.GetInputArgName(o)
 }
 
 func GetInputParamString(o model.Operation) string {
@@ -674,12 +1029,7 @@ func findArgInArray(array []string, toMatch string) bool {
 }
 
 func RequiresParamValidation(o model.Operation) bool {
-	for _, field := range o.InputArgs {
-		if (IsIntArg(field) || IsBoolArg(field) || IsStringSliceArg(field) || IsStringArg(field)) && IsInputArgMandatory(o, field) {
-			return true
-		}
-	}
-	return false
+	return ArgTypes{}.RequiresParamValidation(o)
 }
 
 func IsInputArgMandatory(o model.Operation, arg model.Field) bool {
@@ -706,10 +1056,7 @@ func HasUpload(o model.Operation) bool {
 }
 
 func IsInputArg(arg model.Field) bool {
-	if IsCustomArg(arg) && !IsContextArg(arg) && !IsRequestContextArg(arg) {
-		return true
-	}
-	return false
+	return ArgTypes{}.IsInputArg(arg)
 }
 
 func IsErrorArg(f model.Field) bool {
@@ -753,7 +1100,7 @@ func IsDateArg(f model.Field) bool {
 }
 
 func IsCustomArg(f model.Field) bool {
-	return f.IsCustom()
+	return ArgTypes{}.IsCustomArg(f)
 }
 
 func ToFirstUpper(in string) string {
@@ -779,3 +1126,74 @@ func Uncapitalized(in string) string {
 	}
 	return string(out)
 }
+
+func GetClientName(s model.Struct) string {
+	return ToFirstUpper(s.Name) + "Client"
+}
+
+// IsClientOperation tells if the client gets a method for the operation: operations that handle the http-request
+// themselves are left out
+func IsClientOperation(o model.Operation) bool {
+	return IsRestOperation(o) && IsRestOperationGenerated(o) && !HasUpload(o)
+}
+
+func IsClientBodyMethod(o model.Operation) bool {
+	method := GetRestOperationMethod(o)
+	return method == "POST" || method == "PUT" || method == "PATCH"
+}
+
+func IsClientQueryParam(o model.Operation, arg model.Field) bool {
+	return ArgTypes{}.IsClientQueryParam(o, arg)
+}
+
+func GetClientMethodName(o model.Operation) string {
+	return ToFirstUpper(o.Name)
+}
+
+func GetClientParams(o model.Operation) string {
+	return ArgTypes{}.GetClientParams(o)
+}
+
+// GetClientResultType returns the type of the decoded response: the raw body when the response is no json
+func GetClientResultType(o model.Operation) string {
+	if IsRestOperationJSON(o) {
+		return GetOutputArgType(o)
+	}
+	return "[]byte"
+}
+
+func GetClientResults(o model.Operation) string {
+	if HasOutput(o) {
+		return fmt.Sprintf("(%s, error)", GetClientResultType(o))
+	}
+	return "error"
+}
+
+func getInputArgTypeName(o model.Operation, name string) string {
+	for _, arg := range o.InputArgs {
+		if arg.Name == name {
+			return arg.TypeName
+		}
+	}
+	return ""
+}
+
+// GetClientPathExpression returns the go-expression that fills in the path-parameters of the path of the operation
+func GetClientPathExpression(s model.Struct, o model.Operation) string {
+	path := GetRestServicePath(s) + GetRestOperationPath(o)
+
+	parts := []string{}
+	last := 0
+	for _, loc := range pathVariable.FindAllStringSubmatchIndex(path, -1) {
+		if loc[0] > last {
+			parts = append(parts, fmt.Sprintf("%q", path[last:loc[0]]))
+		}
+		name := path[loc[2]:loc[3]]
+		parts = append(parts, fmt.Sprintf("url.PathEscape(%s)", FormatParam(getInputArgTypeName(o, name), name)))
+		last = loc[1]
+	}
+	if last < len(path) || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%q", path[last:]))
+	}
+	return strings.Join(parts, " + ")
+}
@@ -3,9 +3,12 @@ package rest
 import (
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
+	"strings"
 	"testing"
 
+	"github.com/f0rt/golangAnnotations/config"
 	"github.com/f0rt/golangAnnotations/generator/generationUtil"
 	"github.com/f0rt/golangAnnotations/model"
 	"github.com/stretchr/testify/assert"
@@ -15,8 +18,7 @@ func cleanup() {
 	os.Remove(generationUtil.Prefixed("./testData/ast.json"))
 	os.Remove(generationUtil.Prefixed("./testData/httpMyService.go"))
 	os.Remove(generationUtil.Prefixed("./testData/httpMyServiceHelpers_test.go"))
-	os.Remove(generationUtil.Prefixed("./testData/httpClientForMyService.go"))
-	os.Remove(generationUtil.Prefixed("./testData/httpMyServiceHelpers_test.go"))
+	os.Remove(generationUtil.Prefixed("./testData/httpMyServiceClient.go"))
 	os.Remove(generationUtil.Prefixed("./testData/testDataTestLog/httpTestMyService.go"))
 }
 
@@ -47,7 +49,7 @@ func TestGenerateForWeb(t *testing.T) {
 			},
 		})
 	{
-		err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s})
+		err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s}, generationUtil.NewFileOutput())
 		assert.Nil(t, err)
 	}
 
@@ -78,7 +80,675 @@ func TestGenerateForWeb(t *testing.T) {
 			assert.Contains(t, string(data), "func doitTestHelper")
 		}
 	}
+	{
+		data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyServiceClient.go"))
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), "func NewMyServiceClient(baseURL string, httpClient *http.Client) *MyServiceClient {")
+		assert.Contains(t, string(data), "func (cl *MyServiceClient) Doit(c context.Context, uid int, subuid string) error {")
+		assert.Contains(t, string(data), "errorResponse.SetHTTPCode(httpResp.StatusCode)")
+	}
+
+}
+
+func TestGenerateForWebWithServeMux(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{`// @RestService( path = "/api", router = "servemux" )`},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{`// @RestOperation( path = "/person/{uid}", method = "GET", format = "JSON" )`},
+					Name:          "doit",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "uid", TypeName: "string"}},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+	err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s}, generationUtil.NewFileOutput())
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "func (ts *MyService) HTTPHandlerWithRouter(router *http.ServeMux) *http.ServeMux {")
+	assert.Contains(t, string(data), `router.HandleFunc("GET /api/person/{uid}", doit(ts))`)
+	assert.Contains(t, string(data), `router.HandleFunc("GET /api/person/{uid}/{$}", redirectSlash)`)
+	assert.NotContains(t, string(data), "gorilla/mux")
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/testDataTestLog/httpTestMyService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `router.HandleFunc("GET /api/logs.md", writeTestLogsAsMarkdown())`)
+	assert.NotContains(t, string(data), "gorilla/mux")
+}
+
+func TestGenerateForWebWithServeMuxAndTrailingSlashes(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{`// @RestService( path = "/api", router = "servemux" )`},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{`// @RestOperation( path = "/x", method = "GET" )`},
+					Name:          "withoutSlash",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+				{
+					DocLines:      []string{`// @RestOperation( path = "/x/", method = "GET" )`},
+					Name:          "withSlash",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+				{
+					DocLines:      []string{`// @RestOperation( path = "/y/{uid}", method = "GET" )`},
+					Name:          "withUID",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "uid", TypeName: "string"}},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+				{
+					DocLines:      []string{`// @RestOperation( path = "/y/{id}/", method = "GET" )`},
+					Name:          "withID",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "id", TypeName: "string"}},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+	err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s}, generationUtil.NewFileOutput())
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `router.HandleFunc("GET /api/x", withoutSlash(ts))`)
+	assert.Contains(t, string(data), `router.HandleFunc("GET /api/x/{$}", withSlash(ts))`)
+	assert.Contains(t, string(data), `router.HandleFunc("GET /api/y/{uid}", withUID(ts))`)
+	assert.Contains(t, string(data), `router.HandleFunc("GET /api/y/{id}/{$}", withID(ts))`)
+	assert.NotContains(t, string(data), "redirectSlash)")
+
+	// every pattern is registered once, so http.ServeMux accepts them
+	router := http.NewServeMux()
+	for _, o := range s[0].Operations {
+		for _, pattern := range []string{GetServeMuxPattern(s[0], *o), GetServeMuxSlashPattern(s[0], *o)} {
+			if pattern != "" {
+				assert.NotPanics(t, func() { router.HandleFunc(pattern, func(http.ResponseWriter, *http.Request) {}) }, pattern)
+			}
+		}
+	}
+}
+
+func TestGenerateForWebWithTypedParams(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{`// @RestService( path = "/api" )`},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{`// @RestOperation( path = "/person/{id}", method = "GET", format = "JSON", optionalargs = "since,gender" )`},
+					Name:          "doit",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs: []model.Field{
+						{Name: "id", TypeName: "int64"},
+						{Name: "ratio", TypeName: "float64"},
+						{Name: "since", TypeName: "time.Time"},
+						{Name: "pages", TypeName: "[]int"},
+						{Name: "gender", TypeName: "Gender"},
+					},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+	e := []model.Enum{
+		{
+			DocLines:    []string{"// @JsonEnum()"},
+			PackageName: "testData",
+			Name:        "Gender",
+		},
+	}
+	err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s, Enums: e}, generationUtil.NewFileOutput())
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `id, fieldError := httpparser.ExtractInt64(r, "id", true)`)
+	assert.Contains(t, string(data), `ratio, fieldError := httpparser.ExtractFloat(r, "ratio", true)`)
+	assert.Contains(t, string(data), `since, fieldError := httpparser.ExtractTime(r, "since", false)`)
+	assert.Contains(t, string(data), `pages, fieldError := httpparser.ExtractNumberSlice(r, "pages", true)`)
+	assert.Contains(t, string(data), `gender, fieldError := httpparser.ExtractEnum[Gender](r, "gender", false)`)
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyServiceHelpers_test.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "func doitTestURL(id int64, ratio float64, since time.Time, pages []int, gender Gender) string {")
+	assert.Contains(t, string(data), `query.Set("since", since.Format(time.RFC3339Nano))`)
+	assert.Contains(t, string(data), `target := "/api/person/" + url.PathEscape(fmt.Sprint(id))`)
+}
+
+func TestGenerateForWebWithQueryStruct(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{`// @RestService( path = "/api" )`},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{`// @RestOperation( path = "/person", method = "GET", format = "JSON" )`},
+					Name:          "search",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "filter", TypeName: "PersonFilter"}},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "PersonFilter",
+			Fields: []model.Field{
+				{Name: "Name", TypeName: "string", Tag: "`query:\"name,required\"`"},
+				{Name: "Limit", TypeName: "int", Tag: "`query:\"limit,default=10\"`"},
+				{Name: "Tags", TypeName: "[]string", Tag: "`query:\"tag,default=a,b\"`"},
+				{Name: "Internal", TypeName: "string"},
+			},
+		},
+	}
+	err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s}, generationUtil.NewFileOutput())
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "var filter PersonFilter")
+	assert.Contains(t, string(data), `if value, fieldError := httpparser.ExtractString(r, "name", true); fieldError != nil {`)
+	assert.Contains(t, string(data), `httpparser.SetDefault(r, "limit", "10")`)
+	assert.Contains(t, string(data), `httpparser.SetDefault(r, "tag", "a,b")`)
+	assert.Contains(t, string(data), "filter.Tags = value")
+	assert.NotContains(t, string(data), "Internal")
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyServiceClient.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "func (cl *MyServiceClient) Search(c context.Context, filter PersonFilter) error {")
+	assert.Contains(t, string(data), "if filter.Limit != 0 {")
+}
+
+func TestGenerateForWebWithValidatedBody(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{`// @RestService( path = "/api" )`},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{`// @RestOperation( path = "/person", method = "POST", format = "JSON" )`},
+					Name:          "create",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "person", TypeName: "*Person"}},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+		{
+			DocLines:    []string{"// @Validated()"},
+			PackageName: "testData",
+			Name:        "Person",
+			Fields: []model.Field{
+				{Name: "Name", TypeName: "string", Tag: "`json:\"name\" validate:\"required,max=64\"`"},
+			},
+		},
+	}
+	err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s}, generationUtil.NewFileOutput())
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `validationErrors := []errorh.FieldError{}
+		validationErrors = append(validationErrors, person.Validate()...)
+		if len(validationErrors) > 0 {
+			errorh.HandleHTTPError(c, rc, errorh.NewInvalidInputErrorSpecific(0, validationErrors), w, r)`)
+}
+
+func TestGenerateForWebWithUnsupportedQueryField(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{`// @RestService( path = "/api" )`},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{`// @RestOperation( path = "/person", method = "DELETE" )`},
+					Name:          "purge",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "filter", TypeName: "*PersonFilter"}},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+		{
+			PackageName: "testData",
+			Name:        "PersonFilter",
+			Fields:      []model.Field{{Name: "Scores", TypeName: "map[string]int", Tag: "`query:\"scores\"`"}},
+		},
+	}
+	err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s}, generationUtil.NewFileOutput())
+	assert.EqualError(t, err, "Unsupported type map[string]int of query-parameter scores in PersonFilter")
+}
+
+func TestGenerateForWebWithHeaderParams(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{`// @RestService( path = "/api" )`},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{`// @RestOperation( path = "/person", method = "GET", format = "JSON", headers = "X-Tenant-ID:tenantID", cookies = "session:sessionID", optionalargs = "sessionID" )`},
+					Name:          "doit",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs: []model.Field{
+						{Name: "tenantID", TypeName: "string"},
+						{Name: "sessionID", TypeName: "int"},
+						{Name: "name", TypeName: "string"},
+					},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+	err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s}, generationUtil.NewFileOutput())
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `tenantID, fieldError := httpparser.ExtractString(httpparser.HeaderParams(r, "X-Tenant-ID"), "X-Tenant-ID", true)`)
+	assert.Contains(t, string(data), `sessionID, fieldError := httpparser.ExtractNumber(httpparser.CookieParams(r, "session"), "session", false)`)
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyServiceClient.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `header.Add("X-Tenant-ID", tenantID)`)
+	assert.Contains(t, string(data), `header.Add("Cookie", (&http.Cookie{Name: "session", Value: fmt.Sprint(sessionID)}).String())`)
+	assert.NotContains(t, string(data), `query.Set("X-Tenant-ID"`)
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyServiceHelpers_test.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "func doitTestURL(name string) string {")
+	assert.Contains(t, string(data), "TypedHeaders doitTestHeaders")
+	assert.Contains(t, string(data), `header.Add("X-Tenant-ID", request.TypedHeaders.TenantID)`)
+}
+
+func TestGenerateForWebWithInvalidHeaderParams(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	for headers, expected := range map[string]string{
+		"X-Tenant-ID":          "Invalid headers of operation doit: expected name:argument, got 'X-Tenant-ID'",
+		"X-Tenant-ID:tenant":   "Invalid headers of operation doit: unknown argument tenant of X-Tenant-ID",
+		"X-Tenant-ID:tenantID": "Invalid headers of operation doit: argument tenantID of X-Tenant-ID is a path-parameter",
+		"X-Person:person":      "Invalid headers of operation doit: argument person of X-Person has unsupported type Person",
+	} {
+		s := []model.Struct{
+			{
+				DocLines:    []string{`// @RestService( path = "/api" )`},
+				PackageName: "testData",
+				Name:        "MyService",
+				Operations: []*model.Operation{
+					{
+						DocLines:      []string{`// @RestOperation( path = "/{tenantID}", method = "GET", headers = "` + headers + `" )`},
+						Name:          "doit",
+						RelatedStruct: &model.Field{TypeName: "MyService"},
+						InputArgs:     []model.Field{{Name: "tenantID", TypeName: "string"}, {Name: "person", TypeName: "Person"}},
+						OutputArgs:    []model.Field{{TypeName: "error"}},
+					},
+				},
+			},
+		}
+		err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s}, generationUtil.NewFileOutput())
+		assert.EqualError(t, err, expected)
+	}
+}
+
+func TestGenerateForWebWithStatus(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{`// @RestService( path = "/api" )`},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{`// @RestOperation( path = "/person", method = "POST", format = "JSON", status = "201", location = "/person/{result.UID}" )`},
+					Name:          "create",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "person", TypeName: "Person"}},
+					OutputArgs:    []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+				},
+				{
+					DocLines:      []string{`// @RestOperation( path = "/person/{uid}", method = "DELETE", format = "no_content", status = "202" )`},
+					Name:          "remove",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					InputArgs:     []model.Field{{Name: "uid", TypeName: "string"}},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+	err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s}, generationUtil.NewFileOutput())
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `w.Header().Set("Location", "/api/person/"+url.PathEscape(fmt.Sprint(result.UID)))`)
+	assert.Contains(t, string(data), "w.WriteHeader(http.StatusCreated)")
+	assert.Contains(t, string(data), "w.WriteHeader(http.StatusAccepted)")
+	assert.NotContains(t, string(data), "http.StatusNoContent")
+}
+
+func TestGenerateForWebWithInvalidStatus(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	for attributes, expected := range map[string]string{
+		`status = "200 OK"`: "Unsupported status '200 OK' of operation doit: use one of 200, 201, 202, 204, 301, 302, 303, 307, 308",
+		`status = "404"`:    "Unsupported status '404' of operation doit: use one of 200, 201, 202, 204, 301, 302, 303, 307, 308",
+		`status = "303"`:    "Missing location of operation doit: status 303 is a redirect",
+		`status = "201", location = "/person/{uid}"`:   "Invalid location '/person/{uid}' of operation doit: unknown {uid}",
+		`format = "custom", location = "/person/{id}"`: "Unsupported status or location of operation doit: with format custom its HandleResult writes the response",
+	} {
+		s := []model.Struct{
+			{
+				DocLines:    []string{`// @RestService( path = "/api" )`},
+				PackageName: "testData",
+				Name:        "MyService",
+				Operations: []*model.Operation{
+					{
+						DocLines:      []string{`// @RestOperation( path = "/person", method = "POST", ` + attributes + ` )`},
+						Name:          "doit",
+						RelatedStruct: &model.Field{TypeName: "MyService"},
+						InputArgs:     []model.Field{{Name: "id", TypeName: "int"}},
+						OutputArgs:    []model.Field{{TypeName: "error"}},
+					},
+				},
+			},
+		}
+		err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s}, generationUtil.NewFileOutput())
+		assert.EqualError(t, err, expected)
+	}
+}
+
+func TestGenerateForWebWithNoContentStatusAndOutput(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	for format, expected := range map[string]string{
+		"JSON":       "Unsupported status '204' of operation doit: format JSON writes its result as the body, use format no_content or another status",
+		"no_content": "",
+		"XML,JSON":   "Unsupported status '204' of operation doit: format XML writes its result as the body, use format no_content or another status",
+	} {
+		s := []model.Struct{
+			{
+				DocLines:    []string{`// @RestService( path = "/api" )`},
+				PackageName: "testData",
+				Name:        "MyService",
+				Operations: []*model.Operation{
+					{
+						DocLines:      []string{`// @RestOperation( path = "/person", method = "POST", format = "` + format + `", status = "204" )`},
+						Name:          "doit",
+						RelatedStruct: &model.Field{TypeName: "MyService"},
+						InputArgs:     []model.Field{{Name: "id", TypeName: "int"}},
+						OutputArgs:    []model.Field{{TypeName: "*Person"}, {TypeName: "error"}},
+					},
+				},
+			},
+		}
+		err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s}, generationUtil.NewFileOutput())
+		if expected == "" {
+			assert.NoError(t, err, format)
+		} else {
+			assert.EqualError(t, err, expected)
+		}
+	}
+}
+
+func TestGetRestOperationLocation(t *testing.T) {
+	s := model.Struct{
+		DocLines: []string{`//@RestService( path = "/api")`},
+	}
+	o := model.Operation{
+		DocLines:  []string{`//@RestOperation( method = "POST", path = "/tour/{year}", location = "/tour/{year}/etappe/{result.Day}" )`},
+		InputArgs: []model.Field{{Name: "year", TypeName: "int"}},
+	}
+	assert.Equal(t, `"/api/tour/" + url.PathEscape(fmt.Sprint(year)) + "/etappe/" + url.PathEscape(fmt.Sprint(result.Day))`, GetRestOperationLocation(s, o))
+	assert.Equal(t, 200, GetRestOperationStatus(o))
+}
+
+func TestGenerateForWebWithNegotiation(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{`// @RestService( path = "/api" )`},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{`// @RestOperation( path = "/person", method = "GET", format = "JSON,XML,CSV", filename = "persons.csv" )`},
+					Name:          "export",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					OutputArgs:    []model.Field{{TypeName: "*PersonList"}, {TypeName: "error"}},
+				},
+			},
+		},
+	}
+	err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s}, generationUtil.NewFileOutput())
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `offered := []string{"application/json", "application/xml", "text/csv"}`)
+	assert.Contains(t, string(data), "errorh.NewNotAcceptableErrorf(0,")
+	assert.Contains(t, string(data), `case "application/xml":`)
+	assert.Contains(t, string(data), "err = xml.NewEncoder(w).Encode(result)")
+	assert.Contains(t, string(data), "service.exportWriteCSV(w, result)")
+
+	data, err = ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyServiceHelpers_test.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `httpReq.Header.Set("Accept", "application/json")`)
+}
+
+func TestGenerateForWebWithUnsupportedNegotiation(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{`// @RestService( path = "/api" )`},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{`// @RestOperation( path = "/person", method = "GET", format = "JSON,no_content" )`},
+					Name:          "export",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+	err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s}, generationUtil.NewFileOutput())
+	assert.EqualError(t, err, "Unsupported format 'no_content' of operation export: negotiation supports JSON, XML, HTML, CSV, TXT and MD, each once")
+}
+
+func TestGenerateForWebWithXMLOfSliceOrMap(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	for format, expected := range map[string]string{
+		"XML|[]Person":            "Unsupported format XML of operation export: its result []Person has no single root element, return a struct instead",
+		"JSON,XML|map[string]int": "Unsupported format XML of operation export: its result map[string]int has no single root element, return a struct instead",
+		"JSON|[]Person":           "",
+		"XML|*Person":             "",
+	} {
+		cleanup()
+		formats, result, _ := strings.Cut(format, "|")
+		s := []model.Struct{
+			{
+				DocLines:    []string{`// @RestService( path = "/api" )`},
+				PackageName: "testData",
+				Name:        "MyService",
+				Operations: []*model.Operation{
+					{
+						DocLines:      []string{`// @RestOperation( path = "/person", method = "GET", format = "` + formats + `" )`},
+						Name:          "export",
+						RelatedStruct: &model.Field{TypeName: "MyService"},
+						OutputArgs:    []model.Field{{TypeName: result}, {TypeName: "error"}},
+					},
+				},
+			},
+		}
+		err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s}, generationUtil.NewFileOutput())
+		if expected == "" {
+			assert.NoError(t, err, format)
+		} else {
+			assert.EqualError(t, err, expected)
+		}
+	}
+}
 
+func TestArgTypes(t *testing.T) {
+	argTypes := NewArgTypes(nil, []model.Enum{
+		{DocLines: []string{"// @JsonEnum()"}, Name: "Gender"},
+		{Name: "Color"},
+	})
+	assert.True(t, argTypes.IsParamArg(model.Field{TypeName: "Gender"}))
+	assert.Equal(t, "ExtractEnum[Gender]", argTypes.GetExtractFunction(model.Field{TypeName: "Gender"}))
+	assert.False(t, argTypes.IsParamArg(model.Field{TypeName: "Color"}))
+	assert.True(t, argTypes.IsCustomArg(model.Field{TypeName: "Color"}))
+	assert.True(t, argTypes.IsParamArg(model.Field{TypeName: "time.Duration"}))
+	assert.False(t, argTypes.IsCustomArg(model.Field{TypeName: "time.Duration"}))
+}
+
+func TestGenerateForWebWithUnknownRouter(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{`// @RestService( path = "/api", router = "chi" )`},
+			PackageName: "testData",
+			Name:        "MyService",
+		},
+	}
+	err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s}, generationUtil.NewFileOutput())
+	assert.EqualError(t, err, "Unsupported router 'chi' for service MyService: use mux or servemux")
+}
+
+func TestGetServeMuxPattern(t *testing.T) {
+	s := model.Struct{
+		DocLines: []string{`//@RestService( path = "/api")`},
+	}
+	person := model.Operation{
+		DocLines: []string{`//@RestOperation( method = "GET", path = "/person/{uid}")`},
+	}
+	assert.Equal(t, "GET /api/person/{uid}", GetServeMuxPattern(s, person))
+	assert.Equal(t, "GET /api/person/{uid}/{$}", GetServeMuxSlashPattern(s, person))
+
+	persons := model.Operation{
+		DocLines: []string{`//@RestOperation( method = "POST", path = "/person/")`},
+	}
+	assert.Equal(t, "POST /api/person/{$}", GetServeMuxPattern(s, persons))
+	assert.Equal(t, "POST /api/person", GetServeMuxSlashPattern(s, persons))
+
+	root := model.Operation{
+		DocLines: []string{`//@RestOperation( method = "GET", path = "")`},
+	}
+	assert.Equal(t, "GET /{$}", GetServeMuxPattern(model.Struct{DocLines: []string{`//@RestService( path = "")`}}, root))
+	assert.Equal(t, "", GetServeMuxSlashPattern(model.Struct{DocLines: []string{`//@RestService( path = "")`}}, root))
+}
+
+func TestGetRestOperationHandler(t *testing.T) {
+	s := model.Struct{
+		DocLines: []string{`//@RestService( path = "/api", middleware = "tenancy, audit.Log" )`},
+	}
+	o := model.Operation{
+		Name:     "getPerson",
+		DocLines: []string{`//@RestOperation( method = "GET", path = "/person", middleware = "featureFlag" )`},
+	}
+	assert.Equal(t, "tenancy(audit.Log(featureFlag(getPerson(ts))))", GetRestOperationHandler(s, o))
+	assert.Equal(t, "getPerson(ts)", GetRestOperationHandler(model.Struct{DocLines: []string{`//@RestService( path = "/api")`}}, model.Operation{Name: "getPerson"}))
+}
+
+func TestGenerateForWebImportsMiddleware(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	cfg := config.Default()
+	cfg.Imports["audit"] = "github.com/mycompany/audit"
+	generationUtil.SetConfig(cfg)
+	defer generationUtil.SetConfig(config.Default())
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{`// @RestService( path = "/api", middleware = "audit.Log" )`},
+			PackageName: "testData",
+			Name:        "MyService",
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{`// @RestOperation( path = "/person", method = "GET" )`},
+					Name:          "doit",
+					RelatedStruct: &model.Field{TypeName: "MyService"},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+	err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s}, generationUtil.NewFileOutput())
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/httpMyService.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"github.com/mycompany/audit"`)
+	assert.Contains(t, string(data), "audit.Log(doit(ts))")
+}
+
+func TestGenerateForWebWithInvalidMiddleware(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{`// @RestService( path = "/api", middleware = "audit()" )`},
+			PackageName: "testData",
+			Name:        "MyService",
+		},
+	}
+	err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s}, generationUtil.NewFileOutput())
+	assert.EqualError(t, err, "Invalid middleware 'audit()' for service MyService: expected the name of a func(http.HandlerFunc) http.HandlerFunc")
 }
 
 func TestIsRestService(t *testing.T) {
@@ -154,6 +824,43 @@ func TestGetOutputArgType(t *testing.T) {
 	assert.Equal(t, "Person", GetOutputArgType(createOper("DONTCARE")))
 }
 
+func TestGetParamName(t *testing.T) {
+	o := model.Operation{
+		DocLines: []string{`//@RestOperation( method = "GET", path = "/person/{personUID}")`},
+	}
+	assert.Equal(t, "personUID", GetParamName(o, model.Field{Name: "personUID", TypeName: "string"}))
+	assert.Equal(t, "addressUid", GetParamName(o, model.Field{Name: "addressUID", TypeName: "string"}))
+}
+
+func TestGetClientPathExpression(t *testing.T) {
+	s := model.Struct{
+		DocLines: []string{`//@RestService( path = "/api")`},
+	}
+	o := model.Operation{
+		DocLines: []string{`//@RestOperation( method = "GET", path = "/person/{personUID}/address")`},
+	}
+	assert.Equal(t, `"/api/person/" + url.PathEscape(fmt.Sprint(personUID)) + "/address"`, GetClientPathExpression(s, o))
+	assert.Equal(t, `"/api/person/" + url.PathEscape(fmt.Sprint(uid))`, GetClientPathExpression(s, model.Operation{
+		DocLines: []string{`//@RestOperation( method = "GET", path = "/person/{uid}")`},
+	}))
+	assert.Equal(t, `"/api"`, GetClientPathExpression(s, model.Operation{
+		DocLines: []string{`//@RestOperation( method = "GET", path = "")`},
+	}))
+	assert.Equal(t, `"/api/person/" + url.PathEscape(fmt.Sprint(uid))`, GetClientPathExpression(s, model.Operation{
+		DocLines: []string{`//@RestOperation( method = "GET", path = "/person/{uid:[0-9]{1,8}}")`},
+	}))
+}
+
+func TestPathPatterns(t *testing.T) {
+	o := model.Operation{
+		DocLines: []string{`//@RestOperation( method = "GET", path = "/person/{uid:[0-9]{1,8}}/{name}")`},
+	}
+	assert.True(t, IsPathParam(o, model.Field{Name: "uid"}))
+	assert.Equal(t, "[0-9]{1,8}", GetPathParamPattern(o, "uid"))
+	assert.Equal(t, "", GetPathParamPattern(o, "name"))
+	assert.Equal(t, "/person/{uid}/{name}", StripPathPatterns(GetRestOperationPath(o)))
+}
+
 func TestIsPrimitiveTrue(t *testing.T) {
 	f := model.Field{Name: "uid", TypeName: "string"}
 	assert.False(t, IsCustomArg(f))
@@ -0,0 +1,146 @@
+package rest
+
+const httpClientTemplate = `package {{.PackageName}}
+
+{{block "imports" .}}
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	{{RuntimeImports "errorh"}}
+)
+{{end}}
+
+{{ $service := . }}
+{{ $clientName := GetClientName . }}
+
+// {{$clientName}} calls the operations of {{.Name}} over http
+type {{$clientName}} struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// New{{$clientName}} creates a client for the service at baseURL, like "https://example.com". Without httpClient
+// http.DefaultClient is used.
+func New{{$clientName}}(baseURL string, httpClient *http.Client) *{{$clientName}} {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &{{$clientName}}{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: httpClient,
+	}
+}
+
+{{range $oper := .Operations -}}
+	{{if IsClientOperation $oper -}}
+
+// {{GetClientMethodName $oper}} calls {{GetRestOperationMethod $oper}} {{GetRestServicePath $service}}{{GetRestOperationPath $oper}}
+func (cl *{{$clientName}}) {{GetClientMethodName $oper}}({{GetClientParams $oper}}) {{GetClientResults $oper}} {
+	var err error
+	{{if HasOutput $oper -}}
+		var result {{GetClientResultType $oper}}
+	{{end -}}
+
+	query := url.Values{}
+	{{range $oper.InputArgs -}}
+		{{if IsClientQueryParam $oper . -}}
+			{{range GetQueryEncoding $oper . -}}
+				{{.}}
+			{{end -}}
+		{{end -}}
+	{{end -}}
+
+	header := http.Header{}
+	{{range $oper.InputArgs -}}
+		{{range GetHeaderEncoding $oper . .Name -}}
+			{{.}}
+		{{end -}}
+	{{end -}}
+
+	var body io.Reader
+	{{if HasInput $oper -}}
+		payload, err := json.Marshal({{GetInputArgName $oper}})
+		if err != nil {
+			return {{if HasOutput $oper}}result, {{end}}fmt.Errorf("Error encoding request of {{GetClientMethodName $oper}}: %s", err)
+		}
+		body = bytes.NewReader(payload)
+		header.Set("Content-Type", "application/json")
+	{{else if and (IsRestOperationForm $oper) (IsClientBodyMethod $oper) -}}
+		body = strings.NewReader(query.Encode())
+		header.Set("Content-Type", "application/x-www-form-urlencoded")
+		query = url.Values{}
+	{{end -}}
+
+	{{if HasOutput $oper -}}
+		err = cl.do(c, "{{GetRestOperationMethod $oper}}", {{GetClientPathExpression $service $oper}}, query, header, body, {{if IsRestOperationJSON $oper}}&result{{else}}func(data []byte) { result = data }{{end}})
+		return result, err
+	{{else -}}
+		err = cl.do(c, "{{GetRestOperationMethod $oper}}", {{GetClientPathExpression $service $oper}}, query, header, body, nil)
+		return err
+	{{end -}}
+}
+	{{end -}}
+{{end -}}
+
+// do sends the request and decodes a json-response into result; a func(data []byte) receives the raw response.
+// An error-response is returned as *errorh.Error with the http-status of the response.
+func (cl *{{$clientName}}) do(c context.Context, method string, path string, query url.Values, header http.Header, body io.Reader, result interface{}) error {
+	target := cl.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	httpReq, err := http.NewRequestWithContext(c, method, target, body)
+	if err != nil {
+		return fmt.Errorf("Error creating request %s %s: %s", method, target, err)
+	}
+	for name, values := range header {
+		httpReq.Header[name] = values
+	}
+	if _, ok := result.(func([]byte)); !ok && result != nil {
+		httpReq.Header.Set("Accept", "application/json")
+	}
+
+	httpResp, err := cl.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("Error calling %s %s: %s", method, target, err)
+	}
+	defer httpResp.Body.Close()
+
+	data, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return fmt.Errorf("Error reading response of %s %s: %s", method, target, err)
+	}
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		var errorResponse errorh.Error
+		err = json.Unmarshal(data, &errorResponse)
+		if err != nil {
+			errorResponse = errorh.Error{ErrorMessage: fmt.Sprintf("%s %s returned http-status %d: %s", method, target, httpResp.StatusCode, strings.TrimSpace(string(data)))}
+		}
+		errorResponse.SetHTTPCode(httpResp.StatusCode)
+		return &errorResponse
+	}
+
+	switch r := result.(type) {
+	case nil:
+		return nil
+	case func([]byte):
+		r(data)
+		return nil
+	default:
+		err = json.Unmarshal(data, result)
+		if err != nil {
+			return fmt.Errorf("Error decoding response of %s %s: %s", method, target, err)
+		}
+		return nil
+	}
+}
+`
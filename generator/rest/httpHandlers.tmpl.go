@@ -1,20 +1,57 @@
 package rest
 
-const httpHandlersTemplate = `// Generated automatically by golangAnnotations: do not edit manually
-
-package {{.PackageName}}
+const httpHandlersTemplate = `package {{.PackageName}}
 
+{{block "imports" .}}
 import (
 	"encoding/json"
+	"encoding/xml"
+	"fmt"
 	"net/http"
-
-	"cloud.google.com/go/datastore"
+	"net/url"
+	"strings"
 
 	"github.com/gorilla/mux"
+
+	{{RuntimeImports "bus" "ctx" "datastore" "errorh" "eventStore" "httpparser" "mylog" "request"}}
 )
+{{end}}
 
 {{ $service := . }}
 
+{{block "router" .}}
+{{ $service := . -}}
+{{if IsRestServiceServeMux . -}}
+// HTTPHandler registers endpoint in new router
+func (ts *{{.Name}}) HTTPHandler() http.Handler {
+	return ts.HTTPHandlerWithRouter(http.NewServeMux())
+}
+
+// HTTPHandlerWithRouter registers endpoint in existing router
+func (ts *{{.Name}}) HTTPHandlerWithRouter(router *http.ServeMux) *http.ServeMux {
+	// like a strict-slash router: redirect to the path with or without trailing slash
+	redirectSlash := func(w http.ResponseWriter, r *http.Request) {
+		target := *r.URL
+		if strings.HasSuffix(target.Path, "/") {
+			target.Path = strings.TrimSuffix(target.Path, "/")
+		} else {
+			target.Path += "/"
+		}
+		http.Redirect(w, r, target.String(), http.StatusMovedPermanently)
+	}
+
+	{{range .Operations -}}
+		{{if IsRestOperation . -}}
+			router.HandleFunc("{{GetServeMuxPattern $service .}}", {{GetRestOperationHandler $service .}})
+			{{if GetServeMuxSlashPattern $service . -}}
+				router.HandleFunc("{{GetServeMuxSlashPattern $service .}}", redirectSlash)
+			{{end -}}
+		{{end -}}
+	{{end -}}
+
+	return router
+}
+{{else -}}
 // HTTPHandler registers endpoint in new router
 func (ts *{{.Name}}) HTTPHandler() http.Handler {
 	router := mux.NewRouter().StrictSlash(true)
@@ -25,14 +62,26 @@ func (ts *{{.Name}}) HTTPHandler() http.Handler {
 func (ts *{{.Name}}) HTTPHandlerWithRouter(router *mux.Router) *mux.Router {
 	subRouter := router.PathPrefix("{{GetRestServicePath . }}").Subrouter()
 
+	// make the path-parameters available as path-values of the request
+	subRouter.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for name, value := range mux.Vars(r) {
+				r.SetPathValue(name, value)
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+
 	{{range .Operations -}}
 		{{if IsRestOperation . -}}
-			subRouter.HandleFunc("{{GetRestOperationPath . }}", {{.Name}}(ts)).Methods("{{GetRestOperationMethod . }}")
+			subRouter.HandleFunc("{{GetRestOperationPath . }}", {{GetRestOperationHandler $service .}}).Methods("{{GetRestOperationMethod . }}")
 		{{end -}}
 	{{end -}}
 
 	return router
 }
+{{end -}}
+{{end}}
 
 {{ $extractRequestContextMethod := GetExtractRequestContextMethod . }}
 {{ $requiresRoleValidation := DoesRestServiceRequireRoleValidation . }}
@@ -62,6 +111,16 @@ func {{$oper.Name}}(service *{{$service.Name}}) http.HandlerFunc {
 
 		{{end -}}
 
+		{{if IsRestOperationNegotiated . -}}
+			offered := []string{ {{- GetRestOperationMediaTypes . -}} }
+			format, acceptable := httpparser.NegotiateFormat(r, offered...)
+			if !acceptable {
+				errorh.HandleHTTPError(c, rc, errorh.NewNotAcceptableErrorf(0, "Error negotiating format: Accept %s matches none of %s", r.Header.Get("Accept"), strings.Join(offered, ", ")), w, r)
+				return
+			}
+
+		{{end -}}
+
 		{{if HasUpload . -}}
 
 			// Note: blobstore.ParseUpload must be called before parsing request POST-params
@@ -90,59 +149,36 @@ func {{$oper.Name}}(service *{{$service.Name}}) http.HandlerFunc {
 		{{end -}}
 
 		{{range .InputArgs -}}
-
-			{{if not (IsCustomArg .) }}
-				{{if IsIntArg . -}}
-					{{if IsInputArgMandatory $oper . -}}
-						{{.Name}}, fieldError := httpparser.ExtractNumber(r, "{{Uncapitalized .Name}}", true)
-						if fieldError != nil {
-							validationErrors = append(validationErrors, *fieldError)
-						}
-					{{else -}}
-						{{.Name}}, _ := httpparser.ExtractNumber(r, "{{Uncapitalized .Name}}",false)
-					{{end -}}
-				{{else if IsBoolArg . -}}
-					{{if IsInputArgMandatory $oper . -}}
-						{{.Name}}, fieldError := httpparser.ExtractBool(r, "{{Uncapitalized .Name}}", true)
-						if fieldError != nil {
-							validationErrors = append(validationErrors, *fieldError)
-						}
-					{{else -}}
-						{{.Name}}, _ := httpparser.ExtractBool(r, "{{Uncapitalized .Name}}", false)
-					{{end -}}
-				{{else if IsDateArg . -}}
-					{{if IsInputArgMandatory $oper . -}}
-						{{.Name}}, fieldError := httpparser.ExtractDate(r, "{{Uncapitalized .Name}}", true)
-						if fieldError != nil {
-							validationErrors = append(validationErrors, *fieldError)
-						}
-					{{else -}}
-						{{.Name}}, _ := httpparser.ExtractDate(r, "{{Uncapitalized .Name}}", false)
-					{{end -}}
-				{{else if IsStringArg . -}}
-					{{if IsInputArgMandatory $oper . -}}
-						{{.Name}}, fieldError := httpparser.ExtractString(r, "{{Uncapitalized .Name}}", true)
-						if fieldError != nil {
-							validationErrors = append(validationErrors, *fieldError)
-						}
-					{{else -}}
-						{{.Name}}, _ := httpparser.ExtractString(r, "{{Uncapitalized .Name}}", false)
-					{{end -}}
-				{{else if IsStringSliceArg . -}}
-					{{if IsInputArgMandatory $oper . -}}
-						{{.Name}}, fieldError := httpparser.ExtractStringSlice(r, "{{Uncapitalized .Name}}", true)
-						if fieldError != nil {
-							validationErrors = append(validationErrors, *fieldError)
-						}
-					{{else -}}
-						{{.Name}}, _ := httpparser.ExtractStringSlice(r, "{{Uncapitalized .Name}}", false)
+			{{if IsParamArg . -}}
+				{{.Name}}, fieldError := httpparser.{{GetExtractFunction .}}({{GetParamSource $oper .}}, "{{GetParamName $oper .}}", {{IsInputArgMandatory $oper .}})
+				if fieldError != nil {
+					validationErrors = append(validationErrors, *fieldError)
+				}
+			{{else if IsQueryStructArg $oper . -}}
+				{{ $arg := . -}}
+				{{if .IsPointer -}}
+					{{.Name}} := &{{.DereferencedTypeName}}{}
+				{{else -}}
+					var {{.Name}} {{.TypeName}}
+				{{end -}}
+				{{range GetQueryFields . -}}
+					{{if .Default -}}
+						httpparser.SetDefault(r, "{{.Param}}", {{printf "%q" .Default}})
 					{{end -}}
-				{{else}}
-					Force compile error: Input arg {{.}} has unsupported primitive type
+					if value, fieldError := httpparser.{{GetExtractFunction .Field}}(r, "{{.Param}}", {{.Required}}); fieldError != nil {
+						validationErrors = append(validationErrors, *fieldError)
+					} else {
+						{{$arg.Name}}.{{.Field.Name}} = value
+					}
 				{{end -}}
+			{{else if not (IsCustomArg .) -}}
+				Force compile error: Input arg {{.}} has unsupported primitive type
 			{{end -}}
 		{{end -}}
 
+		{{if HasValidatedInput . -}}
+			validationErrors = append(validationErrors, {{GetInputArgName .}}.Validate()...)
+		{{end -}}
 		{{if RequiresParamValidation . -}}
 
 			if len(validationErrors) > 0 {
@@ -217,46 +253,24 @@ func {{$oper.Name}}(service *{{$service.Name}}) http.HandlerFunc {
 			}
 		{{end -}}
 
-		// write OK response body
-		{{if HasContentType . -}}
-			w.Header().Set("Content-Type", "{{GetContentType .}}")
+		{{if HasRestOperationLocation . -}}
+			w.Header().Set("Location", {{GetRestOperationLocation $service .}})
 		{{end -}}
-		{{if IsRestOperationJSON . -}}
-			{{if HasOutput . -}}
-				err = json.NewEncoder(w).Encode(result)
-				if err != nil {
-					mylog.New().Warning(c, rc, "Error writing json-response: %s", err)
-				}
-			{{end -}}
-		{{else if IsRestOperationHTML . -}}
-			{{if HasOutput . -}}
-				err = service.{{$oper.Name}}WriteHTML(w, result)
-				if err != nil {
-					mylog.New().Warning(c, rc, "Error writing html-response: %s", err)
-				}
-			{{else -}}
-				err = service.{{$oper.Name}}WriteHTML(w)
-				if err != nil {
-					mylog.New().Warning(c, rc, "Error writing html-response: %s", err)
-				}
-			{{end -}}
-		{{else if IsRestOperationCSV . -}}
-			w.Header().Set("Content-Disposition", "attachment;filename={{ GetRestOperationFilename .}}")
-			{{if HasOutput . -}}
-				service.{{$oper.Name}}WriteCSV(w, result)
-			{{else -}}
-				{{$oper.Name}}WriteCSV(w)
+
+		{{block "writeResponse" .}}
+		{{if IsRestOperationNegotiated . -}}
+			// write OK response body in the negotiated format
+			{{ $oper := . -}}
+			switch format {
+			{{range GetRestOperationFormats . -}}
+			case "{{GetFormatMediaType .}}":
+				{{template "writeFormat" ForFormat $oper . -}}
 			{{end -}}
-		{{else if IsRestOperationTXT . -}}
-			fmt.Fprint(w, result)
-		{{else if IsRestOperationMD . -}}
-			fmt.Fprint(w, result)
-		{{else if IsRestOperationNoContent . -}}
-			w.WriteHeader(http.StatusNoContent)
-		{{else if IsRestOperationCustom . -}}
-			service.{{$oper.Name}}HandleResult({{GetContextName $oper }}, rc, w, r, result)
+			}
 		{{else -}}
-			errorh.NewInternalErrorf(0, "Not implemented")
+			// write OK response body
+			{{template "writeFormat" ForFormat . (GetRestOperationFormat .)}}
+		{{end -}}
 		{{end -}}
 	}
 }
@@ -274,4 +288,60 @@ func {{$oper.Name}}(service *{{$service.Name}}) http.HandlerFunc {
 		{{end -}}
 	{{end -}}
 {{end}}
+
+{{define "writeFormat" -}}
+	{{ $oper := .Operation -}}
+	{{with GetFormatContentType .Format -}}
+		w.Header().Set("Content-Type", "{{.}}")
+	{{end -}}
+	{{if eq .Format "CSV" -}}
+		w.Header().Set("Content-Disposition", "attachment;filename={{GetRestOperationFilename $oper}}")
+	{{end -}}
+	{{if and (HasRestOperationStatus $oper) (ne .Format "no_content") -}}
+		w.WriteHeader({{GetRestOperationStatusConstant $oper}})
+	{{end -}}
+	{{if eq .Format "JSON" -}}
+		{{if HasOutput $oper -}}
+			err = json.NewEncoder(w).Encode(result)
+			if err != nil {
+				mylog.New().Warning(c, rc, "Error writing json-response: %s", err)
+			}
+		{{end -}}
+	{{else if eq .Format "XML" -}}
+		{{if HasOutput $oper -}}
+			err = xml.NewEncoder(w).Encode(result)
+			if err != nil {
+				mylog.New().Warning(c, rc, "Error writing xml-response: %s", err)
+			}
+		{{end -}}
+	{{else if eq .Format "HTML" -}}
+		{{if HasOutput $oper -}}
+			err = service.{{$oper.Name}}WriteHTML(w, result)
+			if err != nil {
+				mylog.New().Warning(c, rc, "Error writing html-response: %s", err)
+			}
+		{{else -}}
+			err = service.{{$oper.Name}}WriteHTML(w)
+			if err != nil {
+				mylog.New().Warning(c, rc, "Error writing html-response: %s", err)
+			}
+		{{end -}}
+	{{else if eq .Format "CSV" -}}
+		{{if HasOutput $oper -}}
+			service.{{$oper.Name}}WriteCSV(w, result)
+		{{else -}}
+			{{$oper.Name}}WriteCSV(w)
+		{{end -}}
+	{{else if eq .Format "TXT" -}}
+		fmt.Fprint(w, result)
+	{{else if eq .Format "MD" -}}
+		fmt.Fprint(w, result)
+	{{else if eq .Format "no_content" -}}
+		w.WriteHeader({{GetRestOperationStatusConstant $oper}})
+	{{else if eq .Format "custom" -}}
+		service.{{$oper.Name}}HandleResult({{GetContextName $oper}}, rc, w, r, result)
+	{{else -}}
+		errorh.NewInternalErrorf(0, "Not implemented")
+	{{end -}}
+{{end -}}
 `
@@ -0,0 +1,150 @@
+package rest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/f0rt/golangAnnotations/generator/annotation"
+	"github.com/f0rt/golangAnnotations/generator/rest/restAnnotation"
+	"github.com/f0rt/golangAnnotations/model"
+)
+
+// ParamBinding binds a header or a cookie of the request to an argument of an operation, declared like
+// `headers = "X-Tenant-ID:tenantID"`
+type ParamBinding struct {
+	Name string
+	Arg  string
+}
+
+func parseParamBindings(list string) ([]ParamBinding, error) {
+	bindings := []ParamBinding{}
+	for _, item := range splitList(list) {
+		name, arg, ok := strings.Cut(item, ":")
+		name, arg = strings.TrimSpace(name), strings.TrimSpace(arg)
+		if !ok || name == "" || arg == "" {
+			return bindings, fmt.Errorf("expected name:argument, got '%s'", item)
+		}
+		bindings = append(bindings, ParamBinding{Name: name, Arg: arg})
+	}
+	return bindings, nil
+}
+
+func getParamBindings(o model.Operation, attribute string) []ParamBinding {
+	annotations := annotation.NewRegistry(restAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeRestOperation); ok {
+		bindings, _ := parseParamBindings(ann.Attributes[attribute])
+		return bindings
+	}
+	return []ParamBinding{}
+}
+
+func GetRestOperationHeaders(o model.Operation) []ParamBinding {
+	return getParamBindings(o, restAnnotation.ParamHeaders)
+}
+
+func GetRestOperationCookies(o model.Operation) []ParamBinding {
+	return getParamBindings(o, restAnnotation.ParamCookies)
+}
+
+func findParamBinding(bindings []ParamBinding, arg model.Field) (ParamBinding, bool) {
+	for _, binding := range bindings {
+		if binding.Arg == arg.Name {
+			return binding, true
+		}
+	}
+	return ParamBinding{}, false
+}
+
+func IsHeaderParam(o model.Operation, arg model.Field) bool {
+	_, ok := findParamBinding(GetRestOperationHeaders(o), arg)
+	return ok
+}
+
+func IsCookieParam(o model.Operation, arg model.Field) bool {
+	_, ok := findParamBinding(GetRestOperationCookies(o), arg)
+	return ok
+}
+
+// HasHeaderParams tells if the operation binds headers or cookies
+func HasHeaderParams(o model.Operation) bool {
+	for _, arg := range o.InputArgs {
+		if IsHeaderParam(o, arg) || IsCookieParam(o, arg) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetParamSource returns the expression of the request that an httpparser-function extracts the parameter from
+func GetParamSource(o model.Operation, arg model.Field) string {
+	if binding, ok := findParamBinding(GetRestOperationHeaders(o), arg); ok {
+		return fmt.Sprintf("httpparser.HeaderParams(r, %q)", binding.Name)
+	}
+	if binding, ok := findParamBinding(GetRestOperationCookies(o), arg); ok {
+		return fmt.Sprintf("httpparser.CookieParams(r, %q)", binding.Name)
+	}
+	return "r"
+}
+
+// GetHeaderEncoding returns the statements that add a header- or cookie-parameter with the given value to the
+// http.Header named header. A zero value is left out, like an absent header.
+func GetHeaderEncoding(o model.Operation, arg model.Field, value string) []string {
+	add := ""
+	if binding, ok := findParamBinding(GetRestOperationHeaders(o), arg); ok {
+		add = fmt.Sprintf("header.Add(%q, %%s)", binding.Name)
+	} else if binding, ok := findParamBinding(GetRestOperationCookies(o), arg); ok {
+		add = fmt.Sprintf("header.Add(\"Cookie\", (&http.Cookie{Name: %q, Value: %%s}).String())", binding.Name)
+	} else {
+		return []string{}
+	}
+	if strings.HasPrefix(arg.TypeName, "[]") {
+		return []string{
+			fmt.Sprintf("for _, value := range %s {", value),
+			fmt.Sprintf(add, FormatParam(arg.TypeName, "value")),
+			"}",
+		}
+	}
+	return []string{
+		fmt.Sprintf("if %s {", nonZeroCondition(arg.TypeName, value)),
+		fmt.Sprintf(add, FormatParam(arg.TypeName, value)),
+		"}",
+	}
+}
+
+func (at ArgTypes) validateParamBindings(s model.Struct) error {
+	for _, o := range s.Operations {
+		for _, attribute := range []string{restAnnotation.ParamHeaders, restAnnotation.ParamCookies} {
+			annotations := annotation.NewRegistry(restAnnotation.Get())
+			ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeRestOperation)
+			if !ok {
+				continue
+			}
+			bindings, err := parseParamBindings(ann.Attributes[attribute])
+			if err != nil {
+				return fmt.Errorf("Invalid %s of operation %s: %s", attribute, o.Name, err)
+			}
+			for _, binding := range bindings {
+				if err := at.validateParamBinding(*o, binding); err != nil {
+					return fmt.Errorf("Invalid %s of operation %s: %s", attribute, o.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (at ArgTypes) validateParamBinding(o model.Operation, binding ParamBinding) error {
+	for _, arg := range o.InputArgs {
+		if arg.Name != binding.Arg {
+			continue
+		}
+		if !at.IsParamArg(arg) {
+			return fmt.Errorf("argument %s of %s has unsupported type %s", arg.Name, binding.Name, arg.TypeName)
+		}
+		if IsPathParam(o, arg) {
+			return fmt.Errorf("argument %s of %s is a path-parameter", arg.Name, binding.Name)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown argument %s of %s", binding.Arg, binding.Name)
+}
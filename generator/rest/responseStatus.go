@@ -0,0 +1,152 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/f0rt/golangAnnotations/generator/annotation"
+	"github.com/f0rt/golangAnnotations/generator/rest/restAnnotation"
+	"github.com/f0rt/golangAnnotations/model"
+)
+
+// successStatuses are the statuses that an operation can declare, with the constants of net/http
+var successStatuses = map[int]string{
+	http.StatusOK:                "http.StatusOK",
+	http.StatusCreated:           "http.StatusCreated",
+	http.StatusAccepted:          "http.StatusAccepted",
+	http.StatusNoContent:         "http.StatusNoContent",
+	http.StatusMovedPermanently:  "http.StatusMovedPermanently",
+	http.StatusFound:             "http.StatusFound",
+	http.StatusSeeOther:          "http.StatusSeeOther",
+	http.StatusTemporaryRedirect: "http.StatusTemporaryRedirect",
+	http.StatusPermanentRedirect: "http.StatusPermanentRedirect",
+}
+
+func getRestOperationAttribute(o model.Operation, attribute string) string {
+	annotations := annotation.NewRegistry(restAnnotation.Get())
+	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeRestOperation); ok {
+		return ann.Attributes[attribute]
+	}
+	return ""
+}
+
+// HasRestOperationStatus tells if the operation declares the status of its successful response
+func HasRestOperationStatus(o model.Operation) bool {
+	return getRestOperationAttribute(o, restAnnotation.ParamStatus) != ""
+}
+
+// GetRestOperationStatus returns the status of a successful response: the declared one, or else 204 for
+// format no_content and 200 for the others
+func GetRestOperationStatus(o model.Operation) int {
+	if status, err := strconv.Atoi(getRestOperationAttribute(o, restAnnotation.ParamStatus)); err == nil {
+		return status
+	}
+	if IsRestOperationNoContent(o) {
+		return http.StatusNoContent
+	}
+	return http.StatusOK
+}
+
+func GetRestOperationStatusConstant(o model.Operation) string {
+	return successStatuses[GetRestOperationStatus(o)]
+}
+
+func HasRestOperationLocation(o model.Operation) bool {
+	return getRestOperationAttribute(o, restAnnotation.ParamLocation) != ""
+}
+
+var locationPlaceholder = regexp.MustCompile(`\{([A-Za-z_]\w*)((\.[A-Za-z_]\w*)*)\}`)
+
+// GetRestOperationLocation returns the expression of the Location-header of a successful response. The location is
+// relative to the path of the service, like the path of the operation. Its placeholders refer to the result, like
+// {result.UID}, or to an input-argument.
+func GetRestOperationLocation(s model.Struct, o model.Operation) string {
+	location := GetRestServicePath(s) + getRestOperationAttribute(o, restAnnotation.ParamLocation)
+
+	parts := []string{}
+	last := 0
+	for _, loc := range locationPlaceholder.FindAllStringSubmatchIndex(location, -1) {
+		if loc[0] > last {
+			parts = append(parts, fmt.Sprintf("%q", location[last:loc[0]]))
+		}
+		root, fields := location[loc[2]:loc[3]], location[loc[4]:loc[5]]
+		typeName := ""
+		if fields == "" {
+			typeName = getInputArgTypeName(o, root)
+		}
+		parts = append(parts, fmt.Sprintf("url.PathEscape(%s)", FormatParam(typeName, root+fields)))
+		last = loc[1]
+	}
+	if last < len(location) || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%q", location[last:]))
+	}
+	return strings.Join(parts, " + ")
+}
+
+func validateStatus(s model.Struct) error {
+	for _, o := range s.Operations {
+		if !IsRestOperation(*o) {
+			continue
+		}
+		if IsRestOperationCustom(*o) && (HasRestOperationStatus(*o) || HasRestOperationLocation(*o)) {
+			return fmt.Errorf("Unsupported status or location of operation %s: with format custom its HandleResult writes the response", o.Name)
+		}
+		if status := getRestOperationAttribute(*o, restAnnotation.ParamStatus); status != "" {
+			code, err := strconv.Atoi(status)
+			if _, ok := successStatuses[code]; err != nil || !ok {
+				return fmt.Errorf("Unsupported status '%s' of operation %s: use one of %s", status, o.Name, supportedStatuses())
+			}
+			if code == http.StatusNoContent {
+				if format := getBodyFormat(*o); format != "" {
+					return fmt.Errorf("Unsupported status '%s' of operation %s: format %s writes its result as the body, use format no_content or another status", status, o.Name, format)
+				}
+			}
+		}
+		if !HasRestOperationLocation(*o) {
+			if status := GetRestOperationStatus(*o); status >= 300 {
+				return fmt.Errorf("Missing location of operation %s: status %d is a redirect", o.Name, status)
+			}
+			continue
+		}
+		location := getRestOperationAttribute(*o, restAnnotation.ParamLocation)
+		for _, match := range locationPlaceholder.FindAllStringSubmatch(location, -1) {
+			root := match[1]
+			if root == "result" && HasOutput(*o) || getInputArgTypeName(*o, root) != "" {
+				continue
+			}
+			return fmt.Errorf("Invalid location '%s' of operation %s: unknown {%s}", location, o.Name, root)
+		}
+	}
+	return nil
+}
+
+// getBodyFormat returns the first format in which the operation writes its result as the body of the response
+func getBodyFormat(o model.Operation) string {
+	if !HasOutput(o) {
+		return ""
+	}
+	for _, format := range GetRestOperationFormats(o) {
+		switch format {
+		case "JSON", "XML", "HTML", "CSV", "TXT", "MD":
+			return format
+		}
+	}
+	return ""
+}
+
+func supportedStatuses() string {
+	codes := []int{}
+	for code := range successStatuses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	names := []string{}
+	for _, code := range codes {
+		names = append(names, strconv.Itoa(code))
+	}
+	return strings.Join(names, ", ")
+}
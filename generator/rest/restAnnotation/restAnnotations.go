@@ -13,6 +13,12 @@ const (
 	ParamNoWrap         = "nowrap"
 	ParamAfter          = "after"
 	ParamPath           = "path"
+	ParamRouter         = "router"
+	ParamMiddleware     = "middleware"
+	ParamHeaders        = "headers"
+	ParamCookies        = "cookies"
+	ParamStatus         = "status"
+	ParamLocation       = "location"
 	ParamMethod         = "method"
 	ParamForm           = "form"
 	ParamFormat         = "format"
@@ -26,12 +32,12 @@ func Get() []annotation.AnnotationDescriptor {
 	return []annotation.AnnotationDescriptor{
 		{
 			Name:       TypeRestService,
-			ParamNames: []string{ParamCredentials, ParamNoValidation, ParamProtected, ParamNoTest, ParamPath},
+			ParamNames: []string{ParamCredentials, ParamNoValidation, ParamProtected, ParamNoTest, ParamPath, ParamRouter, ParamMiddleware},
 			Validator:  validateRestServiceAnnotation,
 		},
 		{
 			Name:       TypeRestOperation,
-			ParamNames: []string{ParamNoWrap, ParamAfter, ParamPath, ParamMethod, ParamTransactional, ParamForm, ParamFormat, ParamFilename, ParamOptional, ParamRoles, ParamProducesEvents},
+			ParamNames: []string{ParamNoWrap, ParamAfter, ParamPath, ParamMethod, ParamTransactional, ParamForm, ParamFormat, ParamFilename, ParamOptional, ParamRoles, ParamProducesEvents, ParamMiddleware, ParamHeaders, ParamCookies, ParamStatus, ParamLocation},
 			Validator:  validateRestOperationAnnotation,
 		}}
 }
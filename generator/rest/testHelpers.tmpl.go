@@ -1,9 +1,8 @@
 package rest
 
-const testHelpersTemplate = `// Generated automatically by golangAnnotations: do not edit manually
-
-package {{.PackageName}}
+const testHelpersTemplate = `package {{.PackageName}}
 
+{{block "imports" .}}
 import (
 	"context"
 	"encoding/json"
@@ -11,9 +10,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"net/url"
 	"strings"
 	"testing"
+	"time"
+
+	{{RuntimeImports "envelope" "errorh" "eventStore" "libtest" "mytime" "request"}}
 )
+{{end}}
 
 var (
 	setCookieHook = func(r *http.Request, headers map[string]string) {}
@@ -36,6 +40,7 @@ func TestMain(m *testing.M) {
 }
 
 {{ $serviceName := .Name -}}
+{{ $service := . -}}
 
 type testClient struct {
 	c        context.Context
@@ -55,9 +60,22 @@ func newTestClient(ctx context.Context, testingT *testing.T, testCase *libtest.H
 
 {{if IsRestOperation . -}}
 
+{{if HasHeaderParams . -}}
+// {{.Name}}TestHeaders holds the header- and cookie-parameters of {{.Name}}; zero values are left out
+type {{.Name}}TestHeaders struct {
+	{{ $oper := . -}}
+	{{range .InputArgs -}}
+		{{if or (IsHeaderParam $oper .) (IsCookieParam $oper .) -}}
+			{{ToFirstUpper .Name}} {{.TypeName}}
+		{{end -}}
+	{{end -}}
+}
+
+{{end -}}
 type {{.Name}}TestRequest struct {
 	URL     string
 	Headers map[string]string
+	{{if HasHeaderParams . }}TypedHeaders {{.Name}}TestHeaders{{end}}
 	{{if HasInput . }}Body     {{GetInputArgType . }}{{end}}
 	{{if IsRestOperationForm . }}Form     url.Values{{end}}
 }
@@ -76,6 +94,24 @@ type {{.Name}}TestResponse struct {
 	ErrorBody *errorh.Error
 }
 
+// {{.Name}}TestURL composes the url of {{.Name}} from its path- and query-parameters
+func {{.Name}}TestURL({{GetURLParams .}}) string {
+	query := url.Values{}
+	{{ $oper := . -}}
+	{{range .InputArgs -}}
+		{{if IsClientQueryParam $oper . -}}
+			{{range GetQueryEncoding $oper . -}}
+				{{.}}
+			{{end -}}
+		{{end -}}
+	{{end -}}
+	target := {{GetClientPathExpression $service .}}
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	return target
+}
+
 func {{.Name}}TestHelperWithoutHeaders(t *testing.T, c context.Context, tc *libtest.HTTPTestCase, url string{{if IsRestOperationForm . }}, form url.Values{{else if HasInput . }}, input {{GetInputArgType . }}{{end}}) ({{if IsRestOperationJSON . }}int{{if HasOutput . }}, {{GetOutputArgType . }}{{end}}, *errorh.Error{{else}}*httptest.ResponseRecorder{{end}}, error) {
 	return {{.Name}}TestHelperWithHeaders(t, c, tc, url{{if IsRestOperationForm . }}, form{{else if HasInput . }}, input{{end}}, map[string]string{})
 }
@@ -141,11 +177,20 @@ func (tcl *testClient) {{.Name}}(request {{.Name}}TestRequest) {{.Name}}TestResp
 			httpReq.Header.Set("Content-type", "application/json")
 		{{end -}}
 		{{if HasOutput . -}}
-			httpReq.Header.Set("Accept", "application/json")
+			httpReq.Header.Set("Accept", "{{GetAcceptType .}}")
 		{{end -}}
 		for k, v := range request.Headers {
 			httpReq.Header.Set(k, v)
 		}
+		{{if HasHeaderParams . -}}
+			header := httpReq.Header
+			{{ $oper := . -}}
+			{{range .InputArgs -}}
+				{{range GetHeaderEncoding $oper . (printf "request.TypedHeaders.%s" (ToFirstUpper .Name)) -}}
+					{{.}}
+				{{end -}}
+			{{end -}}
+		{{end -}}
 		setCookieHook(httpReq, request.Headers)
 
 		// record request-part of test-case
@@ -182,16 +227,7 @@ func (tcl *testClient) {{.Name}}(request {{.Name}}TestRequest) {{.Name}}TestResp
 		{{if IsRestOperationJSON . -}}
 			{{if HasOutput . -}}
 
-		       if httpResp.Code == http.StatusFound || httpResp.Code == http.StatusTemporaryRedirect {
-			       return {{.Name}}TestResponse{
-				       StatusCode: httpResp.Code,
-				       HeaderMap:  httpResp.Result().Header,
-				       GetCookie:  getCookie,
-				       Body:       nil,
-			       }
-		        }
-
-				if httpResp.Code != http.StatusOK {
+				if httpResp.Code >= http.StatusBadRequest {
 					// return type-strong error response
 					var errorResponse errorh.Error
 					dec := json.NewDecoder(httpResp.Body)
@@ -208,6 +244,16 @@ func (tcl *testClient) {{.Name}}(request {{.Name}}TestRequest) {{.Name}}TestResp
 					}
 				}
 
+				// the body of a redirect or of a response without content is not decoded
+				if httpResp.Code == http.StatusNoContent || httpResp.Code >= http.StatusMultipleChoices {
+					return {{.Name}}TestResponse{
+						StatusCode: httpResp.Code,
+						HeaderMap:  httpResp.Result().Header,
+						GetCookie:  getCookie,
+						Body:       nil,
+					}
+				}
+
 				// return type-strong success response
 				resp := {{GetOutputArgDeclaration . }}
 				dec := json.NewDecoder(httpResp.Body)
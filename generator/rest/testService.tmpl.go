@@ -1,18 +1,26 @@
 package rest
 
-const testServiceTemplate = `// Generated automatically by golangAnnotations: do not edit manually
-
-package {{.PackageName}}
+const testServiceTemplate = `package {{.PackageName}}
 
+{{block "imports" .}}
 import (
 	"fmt"
 	"net/http"
 
 	"github.com/gorilla/mux"
 )
+{{end}}
 
 var testResults = ""
 
+{{if IsRestServiceServeMux . -}}
+// HTTPTestHandlerWithRouter registers endpoint in existing router
+func HTTPTestHandlerWithRouter(router *http.ServeMux) *http.ServeMux {
+	router.HandleFunc("GET {{GetRestServicePath . }}/logs.md", writeTestLogsAsMarkdown())
+
+	return router
+}
+{{else -}}
 // HTTPTestHandlerWithRouter registers endpoint in existing router
 func HTTPTestHandlerWithRouter(router *mux.Router) *mux.Router {
 	subRouter := router.PathPrefix("{{GetRestServicePath . }}").Subrouter()
@@ -21,6 +29,7 @@ func HTTPTestHandlerWithRouter(router *mux.Router) *mux.Router {
 
 	return router
 }
+{{end}}
 
 func writeTestLogsAsMarkdown() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
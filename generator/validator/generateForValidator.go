@@ -0,0 +1,387 @@
+package validator
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/f0rt/golangAnnotations/generator"
+	"github.com/f0rt/golangAnnotations/generator/annotation"
+	"github.com/f0rt/golangAnnotations/generator/generationUtil"
+	"github.com/f0rt/golangAnnotations/generator/validator/validatorAnnotation"
+	"github.com/f0rt/golangAnnotations/model"
+)
+
+// Generator generates a Validate-method for the structs annotated with @Validated(), from the validate-tags of their
+// fields, like `validate:"required,min=1,max=64"`
+type Generator struct {
+}
+
+func NewGenerator() generator.Generator {
+	return &Generator{}
+}
+
+func (eg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return validatorAnnotation.Get()
+}
+
+func (eg *Generator) GetTemplates() map[string]string {
+	return map[string]string{
+		"validator": validatorTemplate,
+	}
+}
+
+type validatorContext struct {
+	PackageName string
+	Validators  []structValidator
+}
+
+// structValidator holds the statements that validate the fields of a struct
+type structValidator struct {
+	Name       string
+	Statements []string
+}
+
+func (eg *Generator) Generate(inputDir string, parsedSource model.ParsedSources, output generator.Output) error {
+	structs := parsedSource.Structs
+
+	packageName, err := generationUtil.GetPackageNameForEnumsOrStructs(parsedSource.Enums, structs)
+	if packageName == "" || err != nil {
+		return err
+	}
+	for _, s := range structs {
+		if isAnnotated(s) && declaresValidate(s) {
+			report(output, fmt.Sprintf("Not generating a Validate-method for %s: it declares one itself", s.Name))
+		}
+	}
+	validated := GetValidatedStructs(structs)
+	if len(validated) == 0 {
+		return nil
+	}
+	targetDir, err := generationUtil.DetermineTargetPath(inputDir, packageName)
+	if err != nil {
+		return err
+	}
+
+	filenames := []string{}
+	validatorsPerFile := map[string][]structValidator{}
+	for _, s := range structs {
+		if !validated[s.Name] {
+			continue
+		}
+		statements, warnings, err := getStatements(s, validated)
+		if err != nil {
+			return err
+		}
+		for _, warning := range warnings {
+			report(output, warning)
+		}
+		if _, ok := validatorsPerFile[s.Filename]; !ok {
+			filenames = append(filenames, s.Filename)
+		}
+		validatorsPerFile[s.Filename] = append(validatorsPerFile[s.Filename], structValidator{Name: s.Name, Statements: statements})
+	}
+
+	for _, fn := range filenames {
+		targetFilename := strings.Replace(filepath.Base(fn), ".", "_validate.", 1)
+		err = generationUtil.Generate(generationUtil.Info{
+			Src:            packageName,
+			TargetFilename: generationUtil.Prefixed(fmt.Sprintf("%s/%s", targetDir, targetFilename)),
+			TemplateName:   "validator",
+			TemplateString: validatorTemplate,
+			Data: validatorContext{
+				PackageName: packageName,
+				Validators:  validatorsPerFile[fn],
+			},
+			Output: output,
+		})
+		if err != nil {
+			return fmt.Errorf("Error generating validators for %s: %w", fn, err)
+		}
+	}
+	return nil
+}
+
+func report(output generator.Output, diagnostic string) {
+	if reporter, ok := output.(generator.Reporter); ok {
+		reporter.Report(diagnostic)
+	}
+}
+
+// GetValidatedStructs returns the names of the structs that get a Validate-method: the structs annotated with
+// @Validated() that do not declare a Validate-method themselves
+func GetValidatedStructs(structs []model.Struct) map[string]bool {
+	validated := map[string]bool{}
+	for _, s := range structs {
+		if isAnnotated(s) && !declaresValidate(s) {
+			validated[s.Name] = true
+		}
+	}
+	return validated
+}
+
+func isAnnotated(s model.Struct) bool {
+	annotations := annotation.NewRegistry(validatorAnnotation.Get())
+	_, ok := annotations.ResolveAnnotationByName(s.DocLines, validatorAnnotation.TypeValidated)
+	return ok
+}
+
+func declaresValidate(s model.Struct) bool {
+	for _, o := range s.Operations {
+		if o.Name == "Validate" {
+			return true
+		}
+	}
+	return false
+}
+
+func getElementTypeName(f model.Field) string {
+	return strings.TrimPrefix(strings.TrimPrefix(f.TypeName, "[]"), "*")
+}
+
+// getStatements returns the statements that validate the fields of the struct, and warnings about the rules that are
+// skipped
+func getStatements(s model.Struct, validated map[string]bool) ([]string, []string, error) {
+	statements := []string{}
+	warnings := []string{}
+	for _, f := range s.Fields {
+		ruleStatements, skipped, err := getRuleStatements(f)
+		if err != nil {
+			return statements, warnings, fmt.Errorf("Invalid validate-tag of field %s.%s: %s", s.Name, f.Name, err)
+		}
+		for _, name := range skipped {
+			warnings = append(warnings, fmt.Sprintf("Skipping unsupported rule %s in the validate-tag of field %s.%s", name, s.Name, f.Name))
+		}
+		statements = append(statements, ruleStatements...)
+		if validated[getElementTypeName(f)] {
+			statements = append(statements, getNestedStatements(f)...)
+		}
+	}
+	return statements, warnings, nil
+}
+
+type rule struct {
+	Name  string
+	Value string
+}
+
+func parseRules(tag string) []rule {
+	rules := []rule{}
+	for _, item := range strings.Split(tag, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(item), "=")
+		if name != "" {
+			rules = append(rules, rule{Name: name, Value: value})
+		}
+	}
+	return rules
+}
+
+// supportedRules are the rules that are validated; other rules, like those of other validation libraries, are skipped
+var supportedRules = map[string]bool{
+	"required": true, "min": true, "max": true, "email": true, "oneof": true,
+}
+
+var numberTypes = map[string]bool{
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"float32": true, "float64": true,
+}
+
+// getRuleStatements returns a switch that reports the first violated rule of the field, and the names of the rules that
+// are not supported. A zero value only violates rule required: the other rules apply to the values that are present.
+func getRuleStatements(f model.Field) ([]string, []string, error) {
+	skipped := []string{}
+	tag, ok := lookupTag(f, "validate")
+	if !ok || tag == "-" {
+		return []string{}, skipped, nil
+	}
+	name := getFieldName(f)
+	value := "data." + f.Name
+
+	zero := zeroCondition(f.TypeName, value)
+	missing := ""
+	cases := []string{}
+	for _, r := range parseRules(tag) {
+		if !supportedRules[r.Name] {
+			skipped = append(skipped, r.Name)
+			continue
+		}
+		if r.Name == "required" {
+			if zero == "" {
+				return nil, skipped, fmt.Errorf("rule required does not apply to type %s", f.TypeName)
+			}
+			missing = fmt.Sprintf("fieldErrors = append(fieldErrors, httpparser.MissingField(%q))", name)
+			continue
+		}
+		condition, msg, err := getRuleCondition(r, f.TypeName, value)
+		if err != nil {
+			return nil, skipped, err
+		}
+		cases = append(cases,
+			fmt.Sprintf("case %s:", condition),
+			fmt.Sprintf("fieldErrors = append(fieldErrors, httpparser.InvalidField(%q, %q))", name, msg))
+	}
+	if missing == "" && len(cases) == 0 {
+		return []string{}, skipped, nil
+	}
+
+	statements := []string{"switch {", fmt.Sprintf("case %s:", zero)}
+	if missing != "" {
+		statements = append(statements, missing)
+	}
+	statements = append(statements, cases...)
+	return append(statements, "}"), skipped, nil
+}
+
+func getRuleCondition(r rule, typeName string, value string) (string, string, error) {
+	kind := getKind(typeName)
+	switch r.Name {
+	case "min", "max":
+		operator, bound := "<", "at least"
+		if r.Name == "max" {
+			operator, bound = ">", "at most"
+		}
+		switch kind {
+		case "string":
+			if _, err := strconv.ParseUint(r.Value, 10, 0); err != nil {
+				return "", "", fmt.Errorf("rule %s expects a length, got '%s'", r.Name, r.Value)
+			}
+			return fmt.Sprintf("utf8.RuneCountInString(%s) %s %s", value, operator, r.Value), fmt.Sprintf("must have %s %s characters", bound, r.Value), nil
+		case "length":
+			if _, err := strconv.ParseUint(r.Value, 10, 0); err != nil {
+				return "", "", fmt.Errorf("rule %s expects a length, got '%s'", r.Name, r.Value)
+			}
+			return fmt.Sprintf("len(%s) %s %s", value, operator, r.Value), fmt.Sprintf("must have %s %s elements", bound, r.Value), nil
+		case "number":
+			if err := parseNumber(typeName, r.Value); err != nil {
+				return "", "", fmt.Errorf("rule %s expects a %s, got '%s'", r.Name, typeName, r.Value)
+			}
+			return fmt.Sprintf("%s %s %s", value, operator, r.Value), fmt.Sprintf("must be %s %s", bound, r.Value), nil
+		}
+	case "email":
+		if kind == "string" {
+			return fmt.Sprintf("!httpparser.IsEmail(%s)", value), "must be an e-mail address", nil
+		}
+	case "oneof":
+		options := strings.Fields(r.Value)
+		if len(options) == 0 {
+			return "", "", fmt.Errorf("rule oneof expects values")
+		}
+		literals := []string{}
+		switch kind {
+		case "string":
+			for _, option := range options {
+				literals = append(literals, strconv.Quote(option))
+			}
+		case "number":
+			for _, option := range options {
+				if err := parseNumber(typeName, option); err != nil {
+					return "", "", fmt.Errorf("rule oneof expects values of type %s, got '%s'", typeName, option)
+				}
+				literals = append(literals, option)
+			}
+		default:
+			return "", "", fmt.Errorf("rule oneof does not apply to type %s", typeName)
+		}
+		return fmt.Sprintf("!slices.Contains([]%s{%s}, %s)", typeName, strings.Join(literals, ", "), value), fmt.Sprintf("must be one of %s", strings.Join(options, ", ")), nil
+	default:
+		return "", "", fmt.Errorf("unsupported rule %s", r.Name)
+	}
+	return "", "", fmt.Errorf("rule %s does not apply to type %s", r.Name, typeName)
+}
+
+func getKind(typeName string) string {
+	switch {
+	case typeName == "string":
+		return "string"
+	case numberTypes[typeName]:
+		return "number"
+	case strings.HasPrefix(typeName, "[]") || strings.HasPrefix(typeName, "map["):
+		return "length"
+	}
+	return ""
+}
+
+func parseNumber(typeName string, value string) error {
+	var err error
+	switch {
+	case strings.HasPrefix(typeName, "float"):
+		_, err = strconv.ParseFloat(value, 64)
+	case strings.HasPrefix(typeName, "uint"):
+		_, err = strconv.ParseUint(value, 10, 64)
+	default:
+		_, err = strconv.ParseInt(value, 10, 64)
+	}
+	return err
+}
+
+func zeroCondition(typeName string, value string) string {
+	switch {
+	case typeName == "time.Time":
+		return fmt.Sprintf("%s.IsZero()", value)
+	case typeName == "mydate.MyDate":
+		return fmt.Sprintf("%s == (mydate.MyDate{})", value)
+	case strings.HasPrefix(typeName, "*"):
+		return fmt.Sprintf("%s == nil", value)
+	}
+	switch getKind(typeName) {
+	case "string":
+		return fmt.Sprintf("%s == \"\"", value)
+	case "number":
+		return fmt.Sprintf("%s == 0", value)
+	case "length":
+		return fmt.Sprintf("len(%s) == 0", value)
+	}
+	return ""
+}
+
+// getNestedStatements returns the statements that add the field-errors of a validated struct in the field, with the
+// path of the struct in front of their field-names
+func getNestedStatements(f model.Field) []string {
+	name := getFieldName(f)
+	value := "data." + f.Name
+	collect := func(element string, prefix string) []string {
+		return []string{
+			fmt.Sprintf("for _, fieldError := range %s.Validate() {", element),
+			fmt.Sprintf("fieldError.Field = %s + fieldError.Field", prefix),
+			"fieldErrors = append(fieldErrors, fieldError)",
+			"}",
+		}
+	}
+	statements := []string{}
+	switch {
+	case strings.HasPrefix(f.TypeName, "[]*"):
+		statements = append(statements, fmt.Sprintf("for i, element := range %s {", value), "if element != nil {")
+		statements = append(statements, collect("element", fmt.Sprintf("fmt.Sprintf(\"%s[%%d].\", i)", name))...)
+		statements = append(statements, "}", "}")
+	case strings.HasPrefix(f.TypeName, "[]"):
+		statements = append(statements, fmt.Sprintf("for i, element := range %s {", value))
+		statements = append(statements, collect("element", fmt.Sprintf("fmt.Sprintf(\"%s[%%d].\", i)", name))...)
+		statements = append(statements, "}")
+	case strings.HasPrefix(f.TypeName, "*"):
+		statements = append(statements, fmt.Sprintf("if %s != nil {", value))
+		statements = append(statements, collect(value, strconv.Quote(name+"."))...)
+		statements = append(statements, "}")
+	default:
+		statements = append(statements, collect(value, strconv.Quote(name+"."))...)
+	}
+	return statements
+}
+
+// getFieldName returns the name of the field in the json of the request
+func getFieldName(f model.Field) string {
+	if tag, ok := lookupTag(f, "json"); ok {
+		if name := strings.Split(tag, ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+// lookupTag returns the value of a tag of the field. Unlike model.Field.GetTagMap it keeps the spaces in a value,
+// like in `validate:"oneof=male female"`.
+func lookupTag(f model.Field, key string) (string, bool) {
+	return reflect.StructTag(strings.Trim(f.Tag, "`")).Lookup(key)
+}
@@ -0,0 +1,169 @@
+package validator
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/f0rt/golangAnnotations/generator/generationUtil"
+	"github.com/f0rt/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func cleanup() {
+	os.Remove(generationUtil.Prefixed("./testData/example_validate.go"))
+}
+
+func TestGenerateForValidator(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{"// @Validated()"},
+			PackageName: "testData",
+			Filename:    "example.go",
+			Name:        "Address",
+			Fields: []model.Field{
+				{Name: "Street", TypeName: "string", Tag: "`json:\"street\" validate:\"required,max=64\"`"},
+				{Name: "Zip", TypeName: "string", Tag: "`json:\"zip\"`"},
+			},
+		},
+		{
+			DocLines:    []string{"// @Validated()"},
+			PackageName: "testData",
+			Filename:    "example.go",
+			Name:        "Person",
+			Fields: []model.Field{
+				{Name: "Name", TypeName: "string", Tag: "`json:\"name\" validate:\"required,min=1,max=64\"`"},
+				{Name: "Email", TypeName: "string", Tag: "`json:\"email\" validate:\"email\"`"},
+				{Name: "Gender", TypeName: "string", Tag: "`json:\"gender\" validate:\"oneof=male female\"`"},
+				{Name: "Age", TypeName: "int", Tag: "`json:\"age\" validate:\"min=18,max=130\"`"},
+				{Name: "Tags", TypeName: "[]string", Tag: "`json:\"tags\" validate:\"max=3\"`"},
+				{Name: "Address", TypeName: "*Address", Tag: "`json:\"address\" validate:\"required\"`"},
+				{Name: "Addresses", TypeName: "[]Address", Tag: "`json:\"addresses\"`"},
+			},
+		},
+		{
+			PackageName: "testData",
+			Filename:    "example.go",
+			Name:        "NotAnnotated",
+			Fields: []model.Field{
+				{Name: "Name", TypeName: "string", Tag: "`json:\"name\" validate:\"required,uuid4\"`"},
+			},
+		},
+	}
+
+	err := NewGenerator().Generate("./testData/", model.ParsedSources{Structs: s}, generationUtil.NewFileOutput())
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/example_validate.go"))
+	assert.NoError(t, err)
+	source := string(data)
+	assert.Contains(t, source, "func (data Address) Validate() []errorh.FieldError {")
+	assert.Contains(t, source, "func (data Person) Validate() []errorh.FieldError {")
+	assert.NotContains(t, source, "NotAnnotated")
+
+	assert.Contains(t, source, `case data.Name == "":
+		fieldErrors = append(fieldErrors, httpparser.MissingField("name"))
+	case utf8.RuneCountInString(data.Name) < 1:
+		fieldErrors = append(fieldErrors, httpparser.InvalidField("name", "must have at least 1 characters"))
+	case utf8.RuneCountInString(data.Name) > 64:`)
+	assert.Contains(t, source, `case !httpparser.IsEmail(data.Email):`)
+	assert.Contains(t, source, `case !slices.Contains([]string{"male", "female"}, data.Gender):`)
+	assert.Contains(t, source, `case data.Age == 0:
+	case data.Age < 18:
+		fieldErrors = append(fieldErrors, httpparser.InvalidField("age", "must be at least 18"))`)
+	assert.Contains(t, source, `case len(data.Tags) > 3:`)
+	assert.Contains(t, source, `case data.Address == nil:
+		fieldErrors = append(fieldErrors, httpparser.MissingField("address"))`)
+	assert.Contains(t, source, `if data.Address != nil {
+		for _, fieldError := range data.Address.Validate() {
+			fieldError.Field = "address." + fieldError.Field`)
+	assert.Contains(t, source, `for i, element := range data.Addresses {
+		for _, fieldError := range element.Validate() {
+			fieldError.Field = fmt.Sprintf("addresses[%d].", i) + fieldError.Field`)
+}
+
+func TestGenerateForValidatorInvalidRule(t *testing.T) {
+	for tag, expected := range map[string]string{
+		`validate:"email"`:   "Invalid validate-tag of field Person.Age: rule email does not apply to type int",
+		`validate:"min=abc"`: "Invalid validate-tag of field Person.Age: rule min expects a int, got 'abc'",
+		`validate:"oneof"`:   "Invalid validate-tag of field Person.Age: rule oneof expects values",
+	} {
+		s := []model.Struct{
+			{
+				DocLines:    []string{"// @Validated()"},
+				PackageName: "testData",
+				Filename:    "example.go",
+				Name:        "Person",
+				Fields:      []model.Field{{Name: "Age", TypeName: "int", Tag: "`" + tag + "`"}},
+			},
+		}
+		err := NewGenerator().Generate("./testData/", model.ParsedSources{Structs: s}, generationUtil.NewFileOutput())
+		if assert.Error(t, err, tag) {
+			assert.Equal(t, expected, err.Error())
+		}
+	}
+}
+
+func TestGenerateForValidatorSkipsUnsupportedRules(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{"// @Validated()"},
+			PackageName: "testData",
+			Filename:    "example.go",
+			Name:        "Person",
+			Fields: []model.Field{
+				{Name: "UID", TypeName: "string", Tag: "`json:\"uid\" validate:\"required,uuid4\"`"},
+				{Name: "Age", TypeName: "int", Tag: "`json:\"age\" validate:\"gte=18\"`"},
+			},
+		},
+	}
+	recorder := generationUtil.NewRecorder(generationUtil.NewFileOutput(), generationUtil.Header{Generator: "validator"})
+	err := NewGenerator().Generate("./testData/", model.ParsedSources{Structs: s}, recorder)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"Skipping unsupported rule uuid4 in the validate-tag of field Person.UID",
+		"Skipping unsupported rule gte in the validate-tag of field Person.Age",
+	}, recorder.Diagnostics())
+
+	data, err := ioutil.ReadFile(generationUtil.Prefixed("./testData/example_validate.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `httpparser.MissingField("uid")`)
+	assert.NotContains(t, string(data), "data.Age")
+}
+
+func TestGenerateForValidatorKeepsDeclaredValidate(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{"// @Validated()"},
+			PackageName: "testData",
+			Filename:    "example.go",
+			Name:        "Person",
+			Fields:      []model.Field{{Name: "Name", TypeName: "string", Tag: "`validate:\"required\"`"}},
+			Operations:  []*model.Operation{{Name: "Validate"}},
+		},
+	}
+	recorder := generationUtil.NewRecorder(generationUtil.NewFileOutput(), generationUtil.Header{Generator: "validator"})
+	err := NewGenerator().Generate("./testData/", model.ParsedSources{Structs: s}, recorder)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Not generating a Validate-method for Person: it declares one itself"}, recorder.Diagnostics())
+	_, err = os.Stat(generationUtil.Prefixed("./testData/example_validate.go"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGetValidatedStructs(t *testing.T) {
+	s := []model.Struct{
+		{Name: "Tour", DocLines: []string{"// @Validated()"}, Fields: []model.Field{{Name: "Etappes", TypeName: "[]*Etappe"}}},
+		{Name: "Etappe", Fields: []model.Field{{Name: "City", TypeName: "string", Tag: "`validate:\"required\"`"}}},
+		{Name: "Cyclist", DocLines: []string{"// @Validated()"}, Operations: []*model.Operation{{Name: "Validate"}}},
+	}
+	assert.Equal(t, map[string]bool{"Tour": true}, GetValidatedStructs(s))
+}
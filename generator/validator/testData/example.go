@@ -0,0 +1,18 @@
+package testData
+
+// @Validated()
+type Address struct {
+	Street string `json:"street" validate:"required,max=64"`
+	Zip    string `json:"zip"`
+}
+
+// @Validated()
+type Person struct {
+	Name      string    `json:"name" validate:"required,min=1,max=64"`
+	Email     string    `json:"email" validate:"email"`
+	Gender    string    `json:"gender" validate:"oneof=male female"`
+	Age       int       `json:"age" validate:"min=18,max=130"`
+	Tags      []string  `json:"tags" validate:"max=3"`
+	Address   *Address  `json:"address" validate:"required"`
+	Addresses []Address `json:"addresses"`
+}
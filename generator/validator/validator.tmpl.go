@@ -0,0 +1,25 @@
+package validator
+
+const validatorTemplate = `package {{.PackageName}}
+
+{{block "imports" .}}
+import (
+	"fmt"
+	"slices"
+	"unicode/utf8"
+
+	{{RuntimeImports "errorh" "httpparser" "mydate"}}
+)
+{{end}}
+
+{{range .Validators}}
+// Validate returns the field-errors of the validate-tags of {{.Name}} and of the structs that it contains
+func (data {{.Name}}) Validate() []errorh.FieldError {
+	fieldErrors := []errorh.FieldError{}
+	{{range .Statements -}}
+		{{.}}
+	{{end -}}
+	return fieldErrors
+}
+{{end}}
+`
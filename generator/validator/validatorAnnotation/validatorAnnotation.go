@@ -0,0 +1,23 @@
+package validatorAnnotation
+
+import "github.com/f0rt/golangAnnotations/generator/annotation"
+
+const (
+	TypeValidated = "Validated"
+)
+
+func Get() []annotation.AnnotationDescriptor {
+	return []annotation.AnnotationDescriptor{
+		{
+			Name:       TypeValidated,
+			ParamNames: []string{},
+			Validator:  validateValidatedAnnotation,
+		}}
+}
+
+func validateValidatedAnnotation(annot annotation.Annotation) bool {
+	if annot.Name == TypeValidated {
+		return true
+	}
+	return false
+}
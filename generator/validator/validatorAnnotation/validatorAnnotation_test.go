@@ -0,0 +1,20 @@
+package validatorAnnotation
+
+import (
+	"testing"
+
+	"github.com/f0rt/golangAnnotations/generator/annotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectValidatedAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.NotEmpty(t, registry.ResolveAnnotations([]string{`// @Validated()`}))
+}
+
+func TestEmptyValidatedAnnotation(t *testing.T) {
+	registry := annotation.NewRegistry(Get())
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{``}))
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path"
 
+	"github.com/f0rt/golangAnnotations/generator/generationUtil"
 	"github.com/f0rt/golangAnnotations/generator/rest"
 	"github.com/f0rt/golangAnnotations/model"
 )
@@ -22,7 +23,7 @@ func main() {
 
 	if triggerRestGenerator {
 		generator := rest.NewGenerator()
-		err = generator.Generate(outputDir, parsedSources)
+		err = generator.Generate(outputDir, parsedSources, generationUtil.NewFileOutput())
 		if err != nil {
 			log.Printf("Error triggering rest-generator: %s", err)
 			os.Exit(-2)
@@ -1,5 +1,18 @@
 module github.com/f0rt/golangAnnotations
 
-go 1.16
+go 1.22
 
-require github.com/stretchr/testify v1.7.0
+require (
+	github.com/f0rt/golangAnnotations/runtime v0.0.0
+	github.com/gorilla/mux v1.8.1
+	github.com/stretchr/testify v1.7.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+)
+
+// the runtime packages are developed together with the generator
+replace github.com/f0rt/golangAnnotations/runtime => ./runtime
@@ -3,62 +3,233 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/f0rt/golangAnnotations/config"
 	"github.com/f0rt/golangAnnotations/generator"
-	"github.com/f0rt/golangAnnotations/generator/ast"
-	"github.com/f0rt/golangAnnotations/generator/event"
-	"github.com/f0rt/golangAnnotations/generator/eventService"
-	"github.com/f0rt/golangAnnotations/generator/jsonHelpers"
-	"github.com/f0rt/golangAnnotations/generator/repository"
-	"github.com/f0rt/golangAnnotations/generator/rest"
-	"github.com/f0rt/golangAnnotations/model"
-	"github.com/f0rt/golangAnnotations/parser"
+	"github.com/f0rt/golangAnnotations/generator/driver"
+	"github.com/f0rt/golangAnnotations/generator/generationUtil"
+	"github.com/f0rt/golangAnnotations/generator/plugin"
+	"github.com/f0rt/golangAnnotations/generator/registry"
 )
 
 const (
 	version = "0.8"
-
-	excludeMatchPattern = "^" + generator.GenfilePrefix + ".*.go$"
 )
 
 var inputDir *string
+var configFile *string
+var templateDir *string
+var dumpTemplatesDir *string
+var generatorsFlag *string
+var skipFlag *string
+var checkFlag *bool
+var pruneFlag *bool
+var watchFlag *bool
+var watchIntervalFlag *time.Duration
+var parallelismFlag *int
+var pluginOptions = pluginOptionsFlag{}
 
 func main() {
 	processArgs()
 
-	parsedSources, err := parser.New().ParseSourceDir(*inputDir, "^.*.go$", excludeMatchPattern)
+	if *dumpTemplatesDir != "" {
+		err := dumpTemplates(*dumpTemplatesDir)
+		if err != nil {
+			log.Printf("Error dumping templates to %s: %s", *dumpTemplatesDir, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	generationUtil.SetTemplateDir(*templateDir)
+	generationUtil.SetVersion(version)
+
+	cfg, err := loadConfig(*configFile, *inputDir)
+	if err != nil {
+		log.Print(err)
+		os.Exit(1)
+	}
+	generationUtil.SetConfig(cfg)
+
+	dirs, err := driver.ExpandInputDir(*inputDir)
 	if err != nil {
-		log.Printf("Error parsing golang sources in %s: %s", *inputDir, err)
+		log.Print(err)
 		os.Exit(1)
 	}
 
-	runAllGenerators(*inputDir, parsedSources)
+	available, warnings := registry.WithPlugins(registry.Builtin(), plugin.Discover(), cfg.Options)
+	for _, w := range warnings {
+		log.Print(w)
+	}
+	options := driver.Options{
+		Available:   available,
+		Selection:   registry.Selection{Include: cfg.Generators, Skip: cfg.Skip},
+		Prune:       cfg.Prune,
+		Parallelism: cfg.Parallelism,
+	}
+
+	if *watchFlag {
+		watch(options)
+		os.Exit(0)
+	}
+
+	if *checkFlag {
+		checker := generationUtil.NewChecker()
+		options.Output = checker
+		exitOnErrors(driver.Run(dirs, options))
+		os.Exit(reportDifferences(checker.Differences()))
+	}
+
+	fileOutput := generationUtil.NewFileOutput()
+	options.Output = fileOutput
+	exitOnErrors(driver.Run(dirs, options))
+	fmt.Fprintf(os.Stderr, "golangAnnotations: %s: %s\n", *inputDir, fileOutput.Summary())
 
 	os.Exit(0)
 }
 
-func runAllGenerators(inputDir string, parsedSources model.ParsedSources) {
-	for name, g := range map[string]generator.Generator{
-		"ast":           ast.NewGenerator("ast.json"),
-		"event":         event.NewGenerator(),
-		"event-service": eventService.NewGenerator(),
-		"json-helpers":  jsonHelpers.NewGenerator(),
-		"rest":          rest.NewGenerator(),
-		"repository":    repository.NewGenerator(),
-	} {
-		err := g.Generate(inputDir, parsedSources)
+// watch regenerates the packages whose sources change until interrupted
+func watch(options driver.Options) {
+	stop := make(chan struct{})
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt)
+	go func() {
+		<-interrupted
+		close(stop)
+	}()
+
+	fmt.Fprintf(os.Stderr, "golangAnnotations: watching %s every %s\n", *inputDir, *watchIntervalFlag)
+	driver.Watch(*inputDir, options, *watchIntervalFlag, stop, func(round driver.Round) {
+		reportErrors(round.Diagnostics, round.Err)
+		if len(round.Dirs) > 0 {
+			fmt.Fprintf(os.Stderr, "golangAnnotations: %s: %s\n", strings.Join(round.Dirs, ", "), round.Summary)
+		}
+	})
+}
+
+// loadConfig reads the configuration file, found from the input-dir upwards unless given explicitly, and applies
+// the command-line flags that are set on top of it
+func loadConfig(filename string, inputDir string) (config.Config, error) {
+	cfg := config.Default()
+	if filename == "" {
+		found, err := config.Find(strings.TrimSuffix(inputDir, driver.RecursiveSuffix))
 		if err != nil {
-			log.Printf("Error generating module %s: %s", name, err)
-			os.Exit(-1)
+			return cfg, fmt.Errorf("Error looking up config for %s: %s", inputDir, err)
+		}
+		filename = found
+	}
+	if filename != "" {
+		loaded, err := config.Load(filename)
+		if err != nil {
+			return cfg, err
+		}
+		cfg = loaded
+	}
+
+	if *generatorsFlag != "" {
+		cfg.Generators = registry.NewSelection(*generatorsFlag, "").Include
+	}
+	if *skipFlag != "" {
+		cfg.Skip = registry.NewSelection("", *skipFlag).Skip
+	}
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "prune":
+			cfg.Prune = *pruneFlag
+		case "j":
+			if *parallelismFlag > 0 {
+				cfg.Parallelism = *parallelismFlag
+			}
+		}
+	})
+	for name, options := range pluginOptions {
+		if cfg.Options[name] == nil {
+			cfg.Options[name] = map[string]string{}
+		}
+		for key, value := range options {
+			cfg.Options[name][key] = value
+		}
+	}
+	return cfg, nil
+}
+
+// exitOnErrors reports the diagnostics and all errors of all packages and generators at once
+func exitOnErrors(diagnostics []string, err error) {
+	if reportErrors(diagnostics, err) > 0 {
+		os.Exit(1)
+	}
+}
+
+// reportErrors prints the diagnostics and errors and returns the number of errors
+func reportErrors(diagnostics []string, err error) int {
+	for _, d := range diagnostics {
+		fmt.Fprintf(os.Stderr, "golangAnnotations: %s\n", d)
+	}
+	if err == nil {
+		return 0
+	}
+	errs, ok := err.(driver.Errors)
+	if !ok {
+		errs = driver.Errors{err}
+	}
+	for _, e := range errs {
+		log.Print(e)
+	}
+	log.Printf("Code generation failed with %d error(s)", len(errs))
+	return len(errs)
+}
+
+// reportDifferences prints the diff of every generated file that is out of date and returns the exit-code
+func reportDifferences(differences []generationUtil.Difference) int {
+	if len(differences) == 0 {
+		return 0
+	}
+	for _, d := range differences {
+		fmt.Print(d.Diff)
+	}
+	for _, d := range differences {
+		log.Printf("Generated file %s is out of date", d.Filename)
+	}
+	return 1
+}
+
+// dumpTemplates writes the built-in templates as a starting point for overrides
+func dumpTemplates(dir string) error {
+	err := os.MkdirAll(dir, 0777)
+	if err != nil {
+		return err
+	}
+	for _, g := range registry.Builtin() {
+		if provider, ok := g.Generator.(generator.TemplateProvider); ok {
+			templates := provider.GetTemplates()
+			templateNames := make([]string, 0, len(templates))
+			for templateName := range templates {
+				templateNames = append(templateNames, templateName)
+			}
+			sort.Strings(templateNames)
+			for _, templateName := range templateNames {
+				templateString := templates[templateName]
+				filename := generationUtil.TemplateOverrideFilename(dir, templateName)
+				err = ioutil.WriteFile(filename, []byte(templateString), 0644)
+				if err != nil {
+					return fmt.Errorf("Error writing template %s of generator %s: %s", templateName, g.Name, err)
+				}
+				fmt.Fprintf(os.Stderr, "%s: Dumped template '%s' of generator '%s' to '%s'\n", "golangAnnotations", templateName, g.Name, filename)
+			}
 		}
 	}
+	return nil
 }
 
 func printUsage() {
 	fmt.Fprintf(os.Stderr, "\nUsage:\n")
-	fmt.Fprintf(os.Stderr, " %s [flags]\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, " %s [flags] [input-dir]\n", os.Args[0])
 	flag.PrintDefaults()
 	fmt.Fprintf(os.Stderr, "\n")
 	os.Exit(1)
@@ -70,7 +241,18 @@ func printVersion() {
 }
 
 func processArgs() {
-	inputDir = flag.String("input-dir", "", "Directory to be examined")
+	inputDir = flag.String("input-dir", "", "Directory to be examined; append /... to include all packages below it")
+	configFile = flag.String("config", "", "Configuration file (default: "+config.Basename+".yaml or .json, looked up from the input-dir upwards)")
+	generatorsFlag = flag.String("generators", "", "Comma separated list of generators to run, in this order (default: all)")
+	skipFlag = flag.String("skip", "", "Comma separated list of generators to skip")
+	templateDir = flag.String("template-dir", "", "Directory with overrides of built-in templates (<template-name>.tmpl)")
+	dumpTemplatesDir = flag.String("dump-templates", "", "Write the built-in templates to this directory and exit")
+	checkFlag = flag.Bool("check", false, "Verify that the generated files are up to date without writing them; prints a diff and exits non-zero otherwise")
+	pruneFlag = flag.Bool("prune", true, "Remove files that an earlier run generated but that are no longer generated; overrides prune of the config")
+	watchFlag = flag.Bool("watch", false, "Keep running and regenerate the packages whose sources change")
+	watchIntervalFlag = flag.Duration("watch-interval", time.Second, "Interval at which the sources are polled in watch-mode")
+	parallelismFlag = flag.Int("j", 0, "Number of generators that run concurrently (default: parallelism of the config, or the number of CPUs)")
+	flag.Var(pluginOptions, "plugin-opt", "Option passed to a generator or plugin as <name>:<key>=<value> (repeatable)")
 	help := flag.Bool("help", false, "Usage information")
 	version := flag.Bool("version", false, "Version information")
 
@@ -82,7 +264,38 @@ func processArgs() {
 	if version != nil && *version == true {
 		printVersion()
 	}
-	if inputDir == nil || *inputDir == "" {
+	if *inputDir == "" && flag.NArg() == 1 {
+		// golangAnnotations -watch ./...
+		*inputDir = flag.Arg(0)
+	}
+	if (inputDir == nil || *inputDir == "") && *dumpTemplatesDir == "" {
+		printUsage()
+	}
+	if *watchFlag && *checkFlag {
+		fmt.Fprintf(os.Stderr, "-watch and -check cannot be combined\n")
 		printUsage()
 	}
 }
+
+// pluginOptionsFlag collects repeated -plugin-opt flags per plugin-name
+type pluginOptionsFlag map[string]map[string]string
+
+func (f pluginOptionsFlag) String() string {
+	return ""
+}
+
+func (f pluginOptionsFlag) Set(value string) error {
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("expected <plugin>:<key>=<value>, got '%s'", value)
+	}
+	keyValue := strings.SplitN(parts[1], "=", 2)
+	if len(keyValue) != 2 || parts[0] == "" || keyValue[0] == "" {
+		return fmt.Errorf("expected <plugin>:<key>=<value>, got '%s'", value)
+	}
+	if f[parts[0]] == nil {
+		f[parts[0]] = map[string]string{}
+	}
+	f[parts[0]][keyValue[0]] = keyValue[1]
+	return nil
+}
@@ -4,11 +4,12 @@ package model
 
 // @JsonStruct()
 type ParsedSources struct {
-	Structs    []Struct    `json:"structs,omitempty"`
-	Operations []Operation `json:"operations,omitempty"`
-	Interfaces []Interface `json:"interfaces,omitempty"`
-	Typedefs   []Typedef   `json:"typedefs,omitempty"`
-	Enums      []Enum      `json:"enums,omitempty"`
+	PackageDocLines []string    `json:"packageDocLines,omitempty"`
+	Structs         []Struct    `json:"structs,omitempty"`
+	Operations      []Operation `json:"operations,omitempty"`
+	Interfaces      []Interface `json:"interfaces,omitempty"`
+	Typedefs        []Typedef   `json:"typedefs,omitempty"`
+	Enums           []Enum      `json:"enums,omitempty"`
 }
 
 // @JsonStruct()
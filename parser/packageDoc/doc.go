@@ -0,0 +1,7 @@
+// Package packageDoc has annotations in its package documentation
+// @Generators( skip = "ast" )
+package packageDoc
+
+type Something struct {
+	X int
+}
@@ -45,11 +45,12 @@ func (p *myParser) ParseSourceDir(dirName string, includeRegex string, excludeRe
 	embedTypedefDocLinesInEnum(v)
 
 	return model.ParsedSources{
-		Structs:    v.Structs,
-		Operations: v.Operations,
-		Interfaces: v.Interfaces,
-		Typedefs:   v.Typedefs,
-		Enums:      v.Enums,
+		PackageDocLines: v.PackageDocLines,
+		Structs:         v.Structs,
+		Operations:      v.Operations,
+		Interfaces:      v.Interfaces,
+		Typedefs:        v.Typedefs,
+		Enums:           v.Enums,
 	}, nil
 }
 
@@ -89,11 +90,12 @@ func parseSourceFile(srcFilename string) (model.ParsedSources, error) {
 	embedTypedefDocLinesInEnum(v)
 
 	return model.ParsedSources{
-		Structs:    v.Structs,
-		Operations: v.Operations,
-		Interfaces: v.Interfaces,
-		Typedefs:   v.Typedefs,
-		Enums:      v.Enums,
+		PackageDocLines: v.PackageDocLines,
+		Structs:         v.Structs,
+		Operations:      v.Operations,
+		Interfaces:      v.Interfaces,
+		Typedefs:        v.Typedefs,
+		Enums:           v.Enums,
 	}, nil
 }
 
@@ -228,6 +230,7 @@ type astVisitor struct {
 	PackageName     string
 	Filename        string
 	Imports         map[string]string
+	PackageDocLines []string
 	Structs         []model.Struct
 	Operations      []model.Operation
 	Interfaces      []model.Interface
@@ -243,6 +246,7 @@ func (v *astVisitor) Visit(node ast.Node) ast.Visitor {
 		if packageName, ok := extractPackageName(node); ok {
 			v.PackageName = packageName
 		}
+		v.PackageDocLines = append(v.PackageDocLines, extractPackageDocLines(node)...)
 
 		// extract all imports into a map
 		v.extractGenDeclImports(node)
@@ -327,6 +331,13 @@ func extractPackageName(node ast.Node) (string, bool) {
 	return "", false
 }
 
+func extractPackageDocLines(node ast.Node) []string {
+	if file, ok := node.(*ast.File); ok && file.Doc != nil {
+		return extractComments(file.Doc)
+	}
+	return []string{}
+}
+
 // ------------------------------------------------------ STRUCT -------------------------------------------------------
 
 func extractGenDeclForStruct(node ast.Node, imports map[string]string, commentMap ast.CommentMap) *model.Struct {
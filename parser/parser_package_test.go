@@ -0,0 +1,22 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePackageDocLines(t *testing.T) {
+	parsedSources, err := parseSourceFile("packageDoc/doc.go")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{
+		"// Package packageDoc has annotations in its package documentation",
+		`// @Generators( skip = "ast" )`,
+	}, parsedSources.PackageDocLines)
+}
+
+func TestParsePackageWithoutDocLines(t *testing.T) {
+	parsedSources, err := parseSourceFile("structs/aStruct.go")
+	assert.Equal(t, nil, err)
+	assert.Empty(t, parsedSources.PackageDocLines)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/f0rt/golangAnnotations/generator"
 	"github.com/f0rt/golangAnnotations/generator/ast"
+	"github.com/f0rt/golangAnnotations/generator/generationUtil"
 	"github.com/f0rt/golangAnnotations/parser"
 )
 
@@ -22,7 +23,7 @@ func main() {
 	}
 
 	jsonAstGenerator := ast.NewGenerator(outputFile)
-	err = jsonAstGenerator.Generate(inputDir, parsedSources)
+	err = jsonAstGenerator.Generate(inputDir, parsedSources, generationUtil.NewFileOutput())
 	if err != nil {
 		log.Printf("Error generating json-ast: %s", err)
 		os.Exit(-1)
@@ -0,0 +1,68 @@
+// Package bus delivers published envelopes to the subscribers of their topic, within the same process
+package bus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/f0rt/golangAnnotations/runtime/envelope"
+	"github.com/f0rt/golangAnnotations/runtime/request"
+)
+
+// Handler receives the envelopes of a topic
+type Handler func(c context.Context, rc request.Context, topic string, envlp envelope.Envelope) error
+
+type subscription struct {
+	subscriber string
+	handler    Handler
+}
+
+var (
+	mutex         sync.RWMutex
+	subscriptions = map[string][]subscription{}
+)
+
+// Subscribe registers a handler for a topic; the topic of an envelope is the name of its aggregate
+func Subscribe(topic string, subscriber string, handler Handler) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	subscriptions[topic] = append(subscriptions[topic], subscription{subscriber: subscriber, handler: handler})
+}
+
+// Reset removes all subscriptions
+func Reset() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	subscriptions = map[string][]subscription{}
+}
+
+// Bus publishes envelopes
+type Bus interface {
+	Publish(c context.Context, rc request.Context, envlp envelope.Envelope) error
+}
+
+type bus struct{}
+
+func New() Bus {
+	return bus{}
+}
+
+// Publish calls the handlers of all subscribers of the topic; all their errors are returned
+func (bus) Publish(c context.Context, rc request.Context, envlp envelope.Envelope) error {
+	topic := envlp.AggregateName
+
+	mutex.RLock()
+	subscribers := append([]subscription{}, subscriptions[topic]...)
+	mutex.RUnlock()
+
+	errs := []error{}
+	for _, s := range subscribers {
+		err := s.handler(c, rc, topic, envlp)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Subscriber %s failed to handle %s: %w", s.subscriber, envlp.NiceName(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
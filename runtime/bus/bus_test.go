@@ -0,0 +1,35 @@
+package bus
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/f0rt/golangAnnotations/runtime/envelope"
+	"github.com/f0rt/golangAnnotations/runtime/request"
+)
+
+func TestPublish(t *testing.T) {
+	defer Reset()
+
+	received := []string{}
+	Subscribe("Tour", "first", func(c context.Context, rc request.Context, topic string, envlp envelope.Envelope) error {
+		received = append(received, "first:"+envlp.EventTypeName)
+		return nil
+	})
+	Subscribe("Tour", "second", func(c context.Context, rc request.Context, topic string, envlp envelope.Envelope) error {
+		return fmt.Errorf("failed")
+	})
+	Subscribe("News", "third", func(c context.Context, rc request.Context, topic string, envlp envelope.Envelope) error {
+		received = append(received, "third:"+envlp.EventTypeName)
+		return nil
+	})
+
+	err := New().Publish(context.Background(), request.NewEmptyContext(), envelope.Envelope{AggregateName: "Tour", EventTypeName: "TourCreated"})
+	if err == nil || err.Error() != "Subscriber second failed to handle Tour.TourCreated(): failed" {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if len(received) != 1 || received[0] != "first:TourCreated" {
+		t.Errorf("Unexpected %v", received)
+	}
+}
@@ -0,0 +1,18 @@
+// Package ctx creates the context.Context in which a request is handled
+package ctx
+
+import (
+	"context"
+	"net/http"
+)
+
+type Creator struct{}
+
+func New() Creator {
+	return Creator{}
+}
+
+// CreateContext returns the context of the http-request
+func (Creator) CreateContext(r *http.Request) context.Context {
+	return r.Context()
+}
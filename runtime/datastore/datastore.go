@@ -0,0 +1,29 @@
+// Package datastore provides transactions for the in-memory stores of the runtime
+package datastore
+
+// Transaction collects changes that are applied together when it commits. A nil transaction applies changes at once.
+type Transaction struct {
+	changes []func()
+}
+
+// OnCommit registers a change; it is discarded when the transaction fails
+func (tx *Transaction) OnCommit(change func()) {
+	if tx == nil {
+		change()
+		return
+	}
+	tx.changes = append(tx.changes, change)
+}
+
+// RunInTransaction commits the changes of fn when it succeeds and discards them when it returns an error
+func RunInTransaction(fn func(tx *Transaction) error) error {
+	tx := &Transaction{}
+	err := fn(tx)
+	if err != nil {
+		return err
+	}
+	for _, change := range tx.changes {
+		change()
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package datastore
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRunInTransaction(t *testing.T) {
+	applied := []string{}
+
+	err := RunInTransaction(func(tx *Transaction) error {
+		tx.OnCommit(func() { applied = append(applied, "committed") })
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = RunInTransaction(func(tx *Transaction) error {
+		tx.OnCommit(func() { applied = append(applied, "rolled back") })
+		return fmt.Errorf("failed")
+	})
+	if err == nil {
+		t.Errorf("Expected error")
+	}
+
+	var tx *Transaction
+	tx.OnCommit(func() { applied = append(applied, "immediate") })
+
+	if fmt.Sprint(applied) != "[committed immediate]" {
+		t.Errorf("Unexpected %v", applied)
+	}
+}
@@ -0,0 +1,27 @@
+// Package devmode tells whether the code runs on a development machine
+package devmode
+
+import "sync/atomic"
+
+// development-mode is on by default: the in-memory runtime has no background workers
+var devMode atomic.Bool
+
+func init() {
+	devMode.Store(true)
+}
+
+// Set switches development-mode on or off
+func Set(on bool) {
+	devMode.Store(on)
+}
+
+type Mode struct{}
+
+func New() Mode {
+	return Mode{}
+}
+
+// IsDevMode makes event services handle events right away instead of enqueueing them
+func (Mode) IsDevMode() bool {
+	return devMode.Load()
+}
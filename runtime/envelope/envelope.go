@@ -0,0 +1,44 @@
+// Package envelope provides the envelope in which events are stored and published
+package envelope
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"time"
+)
+
+// Envelope wraps the json-representation of an event together with its metadata
+type Envelope struct {
+	UUID             string    `json:"uuid"`
+	IsRootEvent      bool      `json:"isRootEvent"`
+	SequenceNumber   int64     `json:"sequenceNumber"`
+	SessionUID       string    `json:"sessionUid"`
+	AdminUserUID     string    `json:"adminUserUid,omitempty"`
+	Timestamp        time.Time `json:"timestamp"`
+	AggregateName    string    `json:"aggregateName"`
+	AggregateUID     string    `json:"aggregateUid"`
+	EventTypeName    string    `json:"eventTypeName"`
+	EventTypeVersion int       `json:"eventTypeVersion"`
+	EventData        string    `json:"eventData"`
+}
+
+// Event is implemented by every event that can be wrapped into an envelope
+type Event interface {
+	GetUID() string
+	GetAggregateName() string
+	GetEventTypeName() string
+}
+
+// CreateRequestUID derives the uuid of the envelope from the uid of the request that produced it: retrying the same
+// request yields the same uuid, so the event is stored only once.
+func (e Envelope) CreateRequestUID(requestUID string) string {
+	b := sha1.Sum([]byte(fmt.Sprintf("%s/%s/%s/%s", requestUID, e.AggregateName, e.AggregateUID, e.EventTypeName)))
+	b[6] = (b[6] & 0x0f) | 0x50 // version 5
+	b[8] = (b[8] & 0x3f) | 0x80 // variant RFC 4122
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// NiceName identifies the envelope in log-messages
+func (e Envelope) NiceName() string {
+	return fmt.Sprintf("%s.%s(%s)", e.AggregateName, e.EventTypeName, e.AggregateUID)
+}
@@ -0,0 +1,55 @@
+package envelope
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCreateRequestUIDIsStable(t *testing.T) {
+	envlp := Envelope{AggregateName: "Tour", AggregateUID: "2016", EventTypeName: "TourCreated"}
+	uid := envlp.CreateRequestUID("request-1")
+	if uid != envlp.CreateRequestUID("request-1") {
+		t.Errorf("Expected the same uuid for the same request")
+	}
+	if uid == envlp.CreateRequestUID("request-2") {
+		t.Errorf("Expected another uuid for another request")
+	}
+	envlp.EventTypeName = "CyclistCreated"
+	if uid == envlp.CreateRequestUID("request-1") {
+		t.Errorf("Expected another uuid for another event")
+	}
+}
+
+func TestFilters(t *testing.T) {
+	moment := time.Date(2016, time.July, 14, 12, 0, 0, 0, time.UTC)
+	envelopes := []Envelope{
+		{UUID: "1", Timestamp: moment.Add(-time.Hour)},
+		{UUID: "2", Timestamp: moment},
+		{UUID: "3", Timestamp: moment.Add(time.Hour)},
+	}
+
+	assertUUIDs(t, AcceptAll, envelopes, "1", "2", "3")
+	assertUUIDs(t, FilterByEventUID{EventUID: "2"}, envelopes, "1", "2")
+	assertUUIDs(t, FilterByMoment{Moment: moment}, envelopes, "1", "2")
+
+	_, err := FilterByEventUID{EventUID: "4"}.FilteredEnvelopes(envelopes)
+	if err == nil {
+		t.Errorf("Expected error for unknown event")
+	}
+}
+
+func assertUUIDs(t *testing.T, filter EnvelopeFilter, envelopes []Envelope, expected ...string) {
+	t.Helper()
+	filtered, err := filter.FilteredEnvelopes(envelopes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filtered) != len(expected) {
+		t.Fatalf("Expected %d envelopes, got %d", len(expected), len(filtered))
+	}
+	for idx, uuid := range expected {
+		if filtered[idx].UUID != uuid {
+			t.Errorf("Expected %s at %d, got %s", uuid, idx, filtered[idx].UUID)
+		}
+	}
+}
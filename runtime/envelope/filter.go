@@ -0,0 +1,49 @@
+package envelope
+
+import (
+	"fmt"
+	"time"
+)
+
+// EnvelopeFilter selects the envelopes of an aggregate that are applied to it
+type EnvelopeFilter interface {
+	FilteredEnvelopes(envelopes []Envelope) ([]Envelope, error)
+}
+
+type acceptAll struct{}
+
+// AcceptAll selects all envelopes
+var AcceptAll EnvelopeFilter = acceptAll{}
+
+func (acceptAll) FilteredEnvelopes(envelopes []Envelope) ([]Envelope, error) {
+	return envelopes, nil
+}
+
+// FilterByEventUID selects the envelopes up to and including the one with the given uuid
+type FilterByEventUID struct {
+	EventUID string
+}
+
+func (f FilterByEventUID) FilteredEnvelopes(envelopes []Envelope) ([]Envelope, error) {
+	for idx, envlp := range envelopes {
+		if envlp.UUID == f.EventUID {
+			return envelopes[:idx+1], nil
+		}
+	}
+	return nil, fmt.Errorf("Event %s not found", f.EventUID)
+}
+
+// FilterByMoment selects the envelopes that were created at or before the given moment
+type FilterByMoment struct {
+	Moment time.Time
+}
+
+func (f FilterByMoment) FilteredEnvelopes(envelopes []Envelope) ([]Envelope, error) {
+	filtered := []Envelope{}
+	for _, envlp := range envelopes {
+		if !envlp.Timestamp.After(f.Moment) {
+			filtered = append(filtered, envlp)
+		}
+	}
+	return filtered, nil
+}
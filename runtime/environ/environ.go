@@ -0,0 +1,36 @@
+// Package environ provides settings that differ per environment
+package environ
+
+import "context"
+
+// Environment is looked up from the context
+type Environment interface {
+	RetryFailedEvents(c context.Context) bool
+}
+
+// Settings is a static Environment
+type Settings struct {
+	RetryFailed bool
+}
+
+func (s Settings) RetryFailedEvents(c context.Context) bool {
+	return s.RetryFailed
+}
+
+// Default retries failed events
+var Default Environment = Settings{RetryFailed: true}
+
+type contextKey struct{}
+
+// WithEnvironment returns a context in which GetEnvironment returns env
+func WithEnvironment(c context.Context, env Environment) context.Context {
+	return context.WithValue(c, contextKey{}, env)
+}
+
+// GetEnvironment returns the environment of the context, or Default
+func GetEnvironment(c context.Context) Environment {
+	if env, ok := c.Value(contextKey{}).(Environment); ok {
+		return env
+	}
+	return Default
+}
@@ -0,0 +1,17 @@
+package environ
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetEnvironment(t *testing.T) {
+	c := context.Background()
+	if !GetEnvironment(c).RetryFailedEvents(c) {
+		t.Errorf("Expected default to retry")
+	}
+	c = WithEnvironment(c, Settings{RetryFailed: false})
+	if GetEnvironment(c).RetryFailedEvents(c) {
+		t.Errorf("Expected environment of context")
+	}
+}
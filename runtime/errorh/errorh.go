@@ -0,0 +1,122 @@
+// Package errorh provides errors that know their http-status and are returned to the client as json
+package errorh
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/f0rt/golangAnnotations/runtime/mylog"
+	"github.com/f0rt/golangAnnotations/runtime/request"
+)
+
+// Error is the json-body of an error-response
+type Error struct {
+	httpCode     int
+	ErrorMessage string       `json:"errorMessage"`
+	ErrorCode    int          `json:"errorCode"`
+	FieldErrors  []FieldError `json:"fieldErrors,omitempty"`
+}
+
+// FieldError describes an invalid field of the request
+type FieldError struct {
+	SubCode int    `json:"subCode"`
+	Field   string `json:"field"`
+	Msg     string `json:"msg"`
+}
+
+// MetaCallback is returned by business logic that handles the http-response itself
+type MetaCallback func(c context.Context, w http.ResponseWriter, r *http.Request) error
+
+func (e Error) Error() string {
+	return e.ErrorMessage
+}
+
+// HTTPCode returns the http-status of the error
+func (e Error) HTTPCode() int {
+	if e.httpCode == 0 {
+		return http.StatusInternalServerError
+	}
+	return e.httpCode
+}
+
+// SetHTTPCode sets the http-status of an error, like a client does for an error-response that it has decoded
+func (e *Error) SetHTTPCode(httpCode int) {
+	e.httpCode = httpCode
+}
+
+func newErrorf(httpCode int, code int, format string, args ...interface{}) error {
+	return &Error{httpCode: httpCode, ErrorCode: code, ErrorMessage: fmt.Sprintf(format, args...)}
+}
+
+func NewInvalidInputErrorf(code int, format string, args ...interface{}) error {
+	return newErrorf(http.StatusBadRequest, code, format, args...)
+}
+
+// NewInvalidInputErrorSpecific reports the fields of the request that are invalid
+func NewInvalidInputErrorSpecific(code int, fieldErrors []FieldError) error {
+	return &Error{httpCode: http.StatusBadRequest, ErrorCode: code, ErrorMessage: "Input validation error", FieldErrors: fieldErrors}
+}
+
+func NewNotAuthenticatedErrorf(code int, format string, args ...interface{}) error {
+	return newErrorf(http.StatusUnauthorized, code, format, args...)
+}
+
+func NewNotAuthorizedErrorf(code int, format string, args ...interface{}) error {
+	return newErrorf(http.StatusForbidden, code, format, args...)
+}
+
+func NewNotFoundErrorf(code int, format string, args ...interface{}) error {
+	return newErrorf(http.StatusNotFound, code, format, args...)
+}
+
+func NewNotAcceptableErrorf(code int, format string, args ...interface{}) error {
+	return newErrorf(http.StatusNotAcceptable, code, format, args...)
+}
+
+func NewConflictErrorf(code int, format string, args ...interface{}) error {
+	return newErrorf(http.StatusConflict, code, format, args...)
+}
+
+func NewInternalErrorf(code int, format string, args ...interface{}) error {
+	return newErrorf(http.StatusInternalServerError, code, format, args...)
+}
+
+// GetHTTPCode returns the http-status of an error: errors that are not created by this package are internal errors
+func GetHTTPCode(err error) int {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.HTTPCode()
+	}
+	return http.StatusInternalServerError
+}
+
+func IsInvalidInputError(err error) bool {
+	return GetHTTPCode(err) == http.StatusBadRequest
+}
+
+func IsNotFoundError(err error) bool {
+	return GetHTTPCode(err) == http.StatusNotFound
+}
+
+// HandleHTTPError writes the error as json-response with the http-status of the error
+func HandleHTTPError(c context.Context, rc request.Context, err error, w http.ResponseWriter, r *http.Request) {
+	body := Error{ErrorMessage: err.Error()}
+	var e *Error
+	if errors.As(err, &e) {
+		body = *e
+	}
+	httpCode := GetHTTPCode(err)
+	if httpCode >= http.StatusInternalServerError {
+		mylog.New().Error(c, rc, "%s %s: %s", r.Method, r.URL.Path, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpCode)
+	err = json.NewEncoder(w).Encode(body)
+	if err != nil {
+		mylog.New().Warning(c, rc, "Error writing error-response: %s", err)
+	}
+}
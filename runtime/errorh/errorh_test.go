@@ -0,0 +1,66 @@
+package errorh
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/f0rt/golangAnnotations/runtime/request"
+)
+
+func TestGetHTTPCode(t *testing.T) {
+	cases := map[error]int{
+		NewInvalidInputErrorf(1, "invalid"):                            http.StatusBadRequest,
+		NewNotFoundErrorf(1, "not found"):                              http.StatusNotFound,
+		NewNotAcceptableErrorf(1, "not acceptable"):                    http.StatusNotAcceptable,
+		fmt.Errorf("wrapped: %w", NewNotAuthorizedErrorf(1, "denied")): http.StatusForbidden,
+		fmt.Errorf("unknown"):                                          http.StatusInternalServerError,
+	}
+	for err, expected := range cases {
+		if got := GetHTTPCode(err); got != expected {
+			t.Errorf("%s: expected %d, got %d", err, expected, got)
+		}
+	}
+}
+
+func TestHandleHTTPError(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := NewInvalidInputErrorSpecific(2, []FieldError{{Field: "year", Msg: "missing"}})
+	HandleHTTPError(context.Background(), request.NewEmptyContext(), err, w, httptest.NewRequest("GET", "/", nil))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected bad request, got %d", w.Code)
+	}
+	var body Error
+	json.NewDecoder(w.Body).Decode(&body)
+	if body.ErrorCode != 2 || len(body.FieldErrors) != 1 || body.FieldErrors[0].Field != "year" {
+		t.Errorf("Unexpected body %+v", body)
+	}
+}
+
+func TestErrorResponseRoundTrip(t *testing.T) {
+	for _, err := range []error{
+		NewInvalidInputErrorSpecific(2, []FieldError{{Field: "year", Msg: "missing"}}),
+		NewNotFoundErrorf(3, "not found"),
+		NewNotAuthorizedErrorf(4, "denied"),
+	} {
+		w := httptest.NewRecorder()
+		HandleHTTPError(context.Background(), request.NewEmptyContext(), err, w, httptest.NewRequest("GET", "/", nil))
+
+		// decoded like a client does
+		var decoded Error
+		if json.NewDecoder(w.Body).Decode(&decoded) != nil {
+			t.Fatalf("%s: error-response is not json", err)
+		}
+		decoded.SetHTTPCode(w.Code)
+		if GetHTTPCode(&decoded) != GetHTTPCode(err) || decoded.ErrorCode != err.(*Error).ErrorCode {
+			t.Errorf("%s: expected http-status %d, got %d", err, GetHTTPCode(err), GetHTTPCode(&decoded))
+		}
+		if IsInvalidInputError(&decoded) != IsInvalidInputError(err) || IsNotFoundError(&decoded) != IsNotFoundError(err) {
+			t.Errorf("%s: classified differently after decoding", err)
+		}
+	}
+}
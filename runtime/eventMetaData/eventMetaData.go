@@ -0,0 +1,29 @@
+// Package eventMetaData describes where an event comes from
+package eventMetaData
+
+import "time"
+
+// Metadata is copied from the envelope of an event
+type Metadata struct {
+	UUID          string    `json:"uuid"`
+	SessionUID    string    `json:"sessionUid,omitempty"`
+	AdminUserUID  string    `json:"adminUserUid,omitempty"`
+	Timestamp     time.Time `json:"timestamp"`
+	AggregateName string    `json:"aggregateName,omitempty"`
+	AggregateUID  string    `json:"aggregateUid,omitempty"`
+	EventTypeName string    `json:"eventTypeName"`
+}
+
+// MetaDataSetter is implemented by aggregates: they keep the metadata of the last event that was applied
+type MetaDataSetter interface {
+	SetMetaData(metadata Metadata)
+}
+
+// LastEvent implements MetaDataSetter; it can be embedded in an aggregate
+type LastEvent struct {
+	Metadata Metadata
+}
+
+func (l *LastEvent) SetMetaData(metadata Metadata) {
+	l.Metadata = metadata
+}
@@ -0,0 +1,174 @@
+// Package eventStore stores the envelopes of events per aggregate
+package eventStore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/f0rt/golangAnnotations/runtime/datastore"
+	"github.com/f0rt/golangAnnotations/runtime/envelope"
+	"github.com/f0rt/golangAnnotations/runtime/request"
+)
+
+// EventStore is the contract of the generated repositories
+type EventStore interface {
+	Put(c context.Context, rc request.Context, tx *datastore.Transaction, envlp *envelope.Envelope) error
+	Search(c context.Context, rc request.Context, tx *datastore.Transaction, aggregateName string, aggregateUID string) ([]envelope.Envelope, error)
+	Exists(c context.Context, rc request.Context, aggregateName string, aggregateUID string) (bool, error)
+	GetAllAggregateUIDs(c context.Context, rc request.Context, aggregateName string) ([]string, error)
+	IterateWithOffset(c context.Context, rc request.Context, aggregateName string, offset time.Time, fn func(envlp envelope.Envelope) error) error
+	IterateAll(c context.Context, rc request.Context, fn func(envlp envelope.Envelope) error) error
+	Purge(c context.Context, rc request.Context, aggregateName string, aggregateUID string, eventUUIDs []string) error
+	PurgeAll(c context.Context, rc request.Context, aggregateName string, eventTypeName string) (bool, error)
+}
+
+// InMemory keeps the envelopes in order of arrival
+type InMemory struct {
+	sync.RWMutex
+	envelopes []envelope.Envelope
+}
+
+func New() *InMemory {
+	return &InMemory{
+		envelopes: []envelope.Envelope{},
+	}
+}
+
+var instance = New()
+
+// Mocked returns the in-memory store that the runtime uses; tests inspect it to verify the events that were stored
+func Mocked() *InMemory {
+	return instance
+}
+
+// RunInTransaction stores the envelopes that are put within fn only when fn succeeds
+func RunInTransaction(c context.Context, rc request.Context, fn func(tx *datastore.Transaction) error) error {
+	return datastore.RunInTransaction(fn)
+}
+
+// Reset removes all envelopes
+func (s *InMemory) Reset() {
+	s.Lock()
+	defer s.Unlock()
+	s.envelopes = []envelope.Envelope{}
+}
+
+// Put assigns the next sequence-number of the aggregate to the envelope. An envelope with a uuid that was stored
+// before is ignored, so retrying a request does not store its events twice.
+func (s *InMemory) Put(c context.Context, rc request.Context, tx *datastore.Transaction, envlp *envelope.Envelope) error {
+	if envlp.AggregateName == "" || envlp.AggregateUID == "" {
+		return fmt.Errorf("Envelope %s has no aggregate", envlp.UUID)
+	}
+	tx.OnCommit(func() {
+		s.Lock()
+		defer s.Unlock()
+		sequenceNumber := int64(0)
+		for _, existing := range s.envelopes {
+			if existing.UUID == envlp.UUID {
+				return
+			}
+			if existing.AggregateName == envlp.AggregateName && existing.AggregateUID == envlp.AggregateUID {
+				sequenceNumber = existing.SequenceNumber
+			}
+		}
+		envlp.SequenceNumber = sequenceNumber + 1
+		s.envelopes = append(s.envelopes, *envlp)
+	})
+	return nil
+}
+
+func (s *InMemory) Search(c context.Context, rc request.Context, tx *datastore.Transaction, aggregateName string, aggregateUID string) ([]envelope.Envelope, error) {
+	found := []envelope.Envelope{}
+	err := s.iterate(func(envlp envelope.Envelope) error {
+		if envlp.AggregateName == aggregateName && envlp.AggregateUID == aggregateUID {
+			found = append(found, envlp)
+		}
+		return nil
+	})
+	return found, err
+}
+
+func (s *InMemory) Exists(c context.Context, rc request.Context, aggregateName string, aggregateUID string) (bool, error) {
+	found, err := s.Search(c, rc, nil, aggregateName, aggregateUID)
+	return len(found) > 0, err
+}
+
+func (s *InMemory) GetAllAggregateUIDs(c context.Context, rc request.Context, aggregateName string) ([]string, error) {
+	uids := map[string]bool{}
+	err := s.iterate(func(envlp envelope.Envelope) error {
+		if envlp.AggregateName == aggregateName {
+			uids[envlp.AggregateUID] = true
+		}
+		return nil
+	})
+	sorted := make([]string, 0, len(uids))
+	for uid := range uids {
+		sorted = append(sorted, uid)
+	}
+	sort.Strings(sorted)
+	return sorted, err
+}
+
+// IterateWithOffset calls fn for the envelopes of the aggregate that were created after offset; a zero offset includes all
+func (s *InMemory) IterateWithOffset(c context.Context, rc request.Context, aggregateName string, offset time.Time, fn func(envlp envelope.Envelope) error) error {
+	return s.iterate(func(envlp envelope.Envelope) error {
+		if envlp.AggregateName != aggregateName || (!offset.IsZero() && !envlp.Timestamp.After(offset)) {
+			return nil
+		}
+		return fn(envlp)
+	})
+}
+
+func (s *InMemory) IterateAll(c context.Context, rc request.Context, fn func(envlp envelope.Envelope) error) error {
+	return s.iterate(fn)
+}
+
+// Purge removes the envelopes with the given uuids from an aggregate
+func (s *InMemory) Purge(c context.Context, rc request.Context, aggregateName string, aggregateUID string, eventUUIDs []string) error {
+	purged := map[string]bool{}
+	for _, uuid := range eventUUIDs {
+		purged[uuid] = true
+	}
+	s.remove(func(envlp envelope.Envelope) bool {
+		return envlp.AggregateName == aggregateName && envlp.AggregateUID == aggregateUID && purged[envlp.UUID]
+	})
+	return nil
+}
+
+// PurgeAll removes the envelopes of an aggregate with the given event-type, or all of them when eventTypeName is empty
+func (s *InMemory) PurgeAll(c context.Context, rc request.Context, aggregateName string, eventTypeName string) (bool, error) {
+	s.remove(func(envlp envelope.Envelope) bool {
+		return envlp.AggregateName == aggregateName && (eventTypeName == "" || envlp.EventTypeName == eventTypeName)
+	})
+	return true, nil
+}
+
+// iterate works on a copy, so fn can use the store itself
+func (s *InMemory) iterate(fn func(envlp envelope.Envelope) error) error {
+	s.RLock()
+	envelopes := append([]envelope.Envelope{}, s.envelopes...)
+	s.RUnlock()
+
+	for _, envlp := range envelopes {
+		err := fn(envlp)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *InMemory) remove(matches func(envlp envelope.Envelope) bool) {
+	s.Lock()
+	defer s.Unlock()
+	kept := []envelope.Envelope{}
+	for _, envlp := range s.envelopes {
+		if !matches(envlp) {
+			kept = append(kept, envlp)
+		}
+	}
+	s.envelopes = kept
+}
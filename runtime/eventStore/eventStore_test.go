@@ -0,0 +1,100 @@
+package eventStore
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/f0rt/golangAnnotations/runtime/datastore"
+	"github.com/f0rt/golangAnnotations/runtime/envelope"
+	"github.com/f0rt/golangAnnotations/runtime/request"
+)
+
+var _ EventStore = New()
+
+func put(t *testing.T, s *InMemory, uuid string, aggregateUID string, eventTypeName string, timestamp time.Time) {
+	t.Helper()
+	err := s.Put(context.Background(), request.NewEmptyContext(), nil, &envelope.Envelope{
+		UUID:          uuid,
+		AggregateName: "Tour",
+		AggregateUID:  aggregateUID,
+		EventTypeName: eventTypeName,
+		Timestamp:     timestamp,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPutAndSearch(t *testing.T) {
+	c, rc := context.Background(), request.NewEmptyContext()
+	moment := time.Date(2016, time.July, 14, 0, 0, 0, 0, time.UTC)
+	s := New()
+	put(t, s, "1", "2016", "TourCreated", moment)
+	put(t, s, "2", "2017", "TourCreated", moment)
+	put(t, s, "3", "2016", "CyclistCreated", moment.Add(time.Hour))
+	put(t, s, "3", "2016", "CyclistCreated", moment.Add(time.Hour)) // ignored: stored before
+
+	found, _ := s.Search(c, rc, nil, "Tour", "2016")
+	if len(found) != 2 || found[0].SequenceNumber != 1 || found[1].SequenceNumber != 2 {
+		t.Errorf("Unexpected %+v", found)
+	}
+
+	exists, _ := s.Exists(c, rc, "Tour", "2018")
+	if exists {
+		t.Errorf("Expected 2018 not to exist")
+	}
+
+	uids, _ := s.GetAllAggregateUIDs(c, rc, "Tour")
+	if fmt.Sprint(uids) != "[2016 2017]" {
+		t.Errorf("Unexpected %v", uids)
+	}
+
+	recent := []string{}
+	s.IterateWithOffset(c, rc, "Tour", moment, func(envlp envelope.Envelope) error {
+		recent = append(recent, envlp.UUID)
+		return nil
+	})
+	if fmt.Sprint(recent) != "[3]" {
+		t.Errorf("Unexpected %v", recent)
+	}
+}
+
+func TestPutInTransaction(t *testing.T) {
+	c, rc := context.Background(), request.NewEmptyContext()
+	s := New()
+
+	RunInTransaction(c, rc, func(tx *datastore.Transaction) error {
+		s.Put(c, rc, tx, &envelope.Envelope{UUID: "1", AggregateName: "Tour", AggregateUID: "2016"})
+		return fmt.Errorf("failed")
+	})
+	RunInTransaction(c, rc, func(tx *datastore.Transaction) error {
+		return s.Put(c, rc, tx, &envelope.Envelope{UUID: "2", AggregateName: "Tour", AggregateUID: "2016"})
+	})
+
+	all := []string{}
+	s.IterateAll(c, rc, func(envlp envelope.Envelope) error {
+		all = append(all, envlp.UUID)
+		return nil
+	})
+	if fmt.Sprint(all) != "[2]" {
+		t.Errorf("Unexpected %v", all)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	c, rc := context.Background(), request.NewEmptyContext()
+	s := New()
+	put(t, s, "1", "2016", "TourCreated", time.Time{})
+	put(t, s, "2", "2016", "CyclistCreated", time.Time{})
+	put(t, s, "3", "2017", "CyclistCreated", time.Time{})
+
+	s.Purge(c, rc, "Tour", "2016", []string{"1"})
+	s.PurgeAll(c, rc, "Tour", "CyclistCreated")
+
+	exists, _ := s.Exists(c, rc, "Tour", "2016")
+	if exists {
+		t.Errorf("Expected all events of 2016 to be purged")
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/f0rt/golangAnnotations/runtime
+
+go 1.22
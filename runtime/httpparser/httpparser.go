@@ -0,0 +1,219 @@
+// Package httpparser extracts the parameters of an operation from the path, the query or the form of a request
+package httpparser
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/f0rt/golangAnnotations/runtime/errorh"
+	"github.com/f0rt/golangAnnotations/runtime/mydate"
+)
+
+// SubCodes of the reported field-errors
+const (
+	SubCodeMissing = 1000 + iota
+	SubCodeInvalid
+)
+
+// values returns the path-value with the given name, or else the query- or form-values
+func values(r *http.Request, name string) []string {
+	if value := r.PathValue(name); value != "" {
+		return []string{value}
+	}
+	if r.Form == nil {
+		r.ParseMultipartForm(32 << 20)
+	}
+	return r.Form[name]
+}
+
+// SetDefault makes the default value the value of an absent parameter, so that it is extracted like a value of the
+// request
+func SetDefault(r *http.Request, name string, value string) {
+	if found := values(r, name); len(found) > 0 && found[0] != "" {
+		return
+	}
+	if r.Form == nil {
+		r.Form = url.Values{}
+	}
+	r.Form[name] = []string{value}
+}
+
+// HeaderParams returns a request with the values of the header as its parameter, so that the header is extracted
+// like a query-parameter
+func HeaderParams(r *http.Request, name string) *http.Request {
+	return &http.Request{Form: url.Values{name: r.Header.Values(name)}}
+}
+
+// CookieParams returns a request with the values of the cookie as its parameter, so that the cookie is extracted
+// like a query-parameter
+func CookieParams(r *http.Request, name string) *http.Request {
+	values := []string{}
+	for _, cookie := range r.Cookies() {
+		if cookie.Name == name {
+			values = append(values, cookie.Value)
+		}
+	}
+	return &http.Request{Form: url.Values{name: values}}
+}
+
+func missing(name string) *errorh.FieldError {
+	return &errorh.FieldError{SubCode: SubCodeMissing, Field: name, Msg: "Missing value for mandatory parameter " + name}
+}
+
+func invalid(name string, value string, err error) *errorh.FieldError {
+	return &errorh.FieldError{SubCode: SubCodeInvalid, Field: name, Msg: "Invalid value " + value + " for parameter " + name + ": " + err.Error()}
+}
+
+func ExtractString(r *http.Request, name string, mandatory bool) (string, *errorh.FieldError) {
+	found := values(r, name)
+	if len(found) == 0 || found[0] == "" {
+		if mandatory {
+			return "", missing(name)
+		}
+		return "", nil
+	}
+	return found[0], nil
+}
+
+// ExtractStringSlice accepts repeated parameters as well as comma-separated values
+func ExtractStringSlice(r *http.Request, name string, mandatory bool) ([]string, *errorh.FieldError) {
+	slice := splitValues(r, name)
+	if len(slice) == 0 && mandatory {
+		return slice, missing(name)
+	}
+	return slice, nil
+}
+
+// splitValues returns the repeated and comma-separated values of the parameter
+func splitValues(r *http.Request, name string) []string {
+	parts := []string{}
+	for _, value := range values(r, name) {
+		for _, part := range strings.Split(value, ",") {
+			if part != "" {
+				parts = append(parts, part)
+			}
+		}
+	}
+	return parts
+}
+
+// extract parses the single value of the parameter; an absent optional parameter yields the zero-value
+func extract[T any](r *http.Request, name string, mandatory bool, parse func(string) (T, error)) (T, *errorh.FieldError) {
+	var result T
+	value, fieldError := ExtractString(r, name, mandatory)
+	if fieldError != nil || value == "" {
+		return result, fieldError
+	}
+	result, err := parse(value)
+	if err != nil {
+		return result, invalid(name, value, err)
+	}
+	return result, nil
+}
+
+// extractSlice parses the repeated and comma-separated values of the parameter
+func extractSlice[T any](r *http.Request, name string, mandatory bool, parse func(string) (T, error)) ([]T, *errorh.FieldError) {
+	slice := []T{}
+	for _, part := range splitValues(r, name) {
+		element, err := parse(part)
+		if err != nil {
+			return slice, invalid(name, part, err)
+		}
+		slice = append(slice, element)
+	}
+	if len(slice) == 0 && mandatory {
+		return slice, missing(name)
+	}
+	return slice, nil
+}
+
+func ExtractNumber(r *http.Request, name string, mandatory bool) (int, *errorh.FieldError) {
+	value, fieldError := ExtractString(r, name, mandatory)
+	if fieldError != nil || value == "" {
+		return 0, fieldError
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, invalid(name, value, err)
+	}
+	return number, nil
+}
+
+func ExtractBool(r *http.Request, name string, mandatory bool) (bool, *errorh.FieldError) {
+	value, fieldError := ExtractString(r, name, mandatory)
+	if fieldError != nil || value == "" {
+		return false, fieldError
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, invalid(name, value, err)
+	}
+	return b, nil
+}
+
+func ExtractDate(r *http.Request, name string, mandatory bool) (mydate.MyDate, *errorh.FieldError) {
+	value, fieldError := ExtractString(r, name, mandatory)
+	if fieldError != nil || value == "" {
+		return mydate.MyDate{}, fieldError
+	}
+	date, err := mydate.Parse(value)
+	if err != nil {
+		return mydate.MyDate{}, invalid(name, value, err)
+	}
+	return date, nil
+}
+
+func ExtractInt64(r *http.Request, name string, mandatory bool) (int64, *errorh.FieldError) {
+	return extract(r, name, mandatory, func(value string) (int64, error) {
+		return strconv.ParseInt(value, 10, 64)
+	})
+}
+
+func ExtractUint(r *http.Request, name string, mandatory bool) (uint, *errorh.FieldError) {
+	return extract(r, name, mandatory, func(value string) (uint, error) {
+		number, err := strconv.ParseUint(value, 10, strconv.IntSize)
+		return uint(number), err
+	})
+}
+
+func ExtractFloat(r *http.Request, name string, mandatory bool) (float64, *errorh.FieldError) {
+	return extract(r, name, mandatory, func(value string) (float64, error) {
+		return strconv.ParseFloat(value, 64)
+	})
+}
+
+// ExtractTime expects a timestamp in RFC 3339 format
+func ExtractTime(r *http.Request, name string, mandatory bool) (time.Time, *errorh.FieldError) {
+	return extract(r, name, mandatory, func(value string) (time.Time, error) {
+		return time.Parse(time.RFC3339, value)
+	})
+}
+
+// ExtractDuration expects a duration like "1h30m"
+func ExtractDuration(r *http.Request, name string, mandatory bool) (time.Duration, *errorh.FieldError) {
+	return extract(r, name, mandatory, time.ParseDuration)
+}
+
+func ExtractNumberSlice(r *http.Request, name string, mandatory bool) ([]int, *errorh.FieldError) {
+	return extractSlice(r, name, mandatory, strconv.Atoi)
+}
+
+func ExtractBoolSlice(r *http.Request, name string, mandatory bool) ([]bool, *errorh.FieldError) {
+	return extractSlice(r, name, mandatory, strconv.ParseBool)
+}
+
+// ExtractEnum parses the name of a json-enum, the way that its UnmarshalJSON does
+func ExtractEnum[T any, PT interface {
+	*T
+	json.Unmarshaler
+}](r *http.Request, name string, mandatory bool) (T, *errorh.FieldError) {
+	return extract(r, name, mandatory, func(value string) (T, error) {
+		var result T
+		err := PT(&result).UnmarshalJSON([]byte(strconv.Quote(value)))
+		return result, err
+	})
+}
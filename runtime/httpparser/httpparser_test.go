@@ -0,0 +1,161 @@
+package httpparser
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/f0rt/golangAnnotations/runtime/mydate"
+)
+
+func TestExtractFromPathAndQuery(t *testing.T) {
+	r := httptest.NewRequest("GET", "/tour/2016?tags=a,b&tags=c&day=2016-07-14&final=true", nil)
+	r.SetPathValue("year", "2016")
+
+	year, fieldError := ExtractNumber(r, "year", true)
+	if fieldError != nil || year != 2016 {
+		t.Errorf("Unexpected %d, %v", year, fieldError)
+	}
+	tags, _ := ExtractStringSlice(r, "tags", true)
+	if !reflect.DeepEqual(tags, []string{"a", "b", "c"}) {
+		t.Errorf("Unexpected %v", tags)
+	}
+	day, _ := ExtractDate(r, "day", true)
+	if day != mydate.New(2016, time.July, 14) {
+		t.Errorf("Unexpected %v", day)
+	}
+	final, _ := ExtractBool(r, "final", false)
+	if !final {
+		t.Errorf("Expected final")
+	}
+}
+
+func TestExtractMissingAndInvalid(t *testing.T) {
+	r := httptest.NewRequest("GET", "/tour?year=twothousand", nil)
+
+	_, fieldError := ExtractString(r, "name", true)
+	if fieldError == nil || fieldError.SubCode != SubCodeMissing || fieldError.Field != "name" {
+		t.Errorf("Expected missing name, got %v", fieldError)
+	}
+	_, fieldError = ExtractString(r, "name", false)
+	if fieldError != nil {
+		t.Errorf("Expected optional name, got %v", fieldError)
+	}
+	_, fieldError = ExtractNumber(r, "year", false)
+	if fieldError == nil || fieldError.SubCode != SubCodeInvalid {
+		t.Errorf("Expected invalid year, got %v", fieldError)
+	}
+}
+
+type color int
+
+func (c *color) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case `"red"`:
+		*c = 1
+	case `"green"`:
+		*c = 2
+	default:
+		return fmt.Errorf("invalid color %s", data)
+	}
+	return nil
+}
+
+func TestExtractTypedParameters(t *testing.T) {
+	r := httptest.NewRequest("GET", "/search?id=12345678901&limit=7&ratio=0.5&since=2016-07-14T12:00:00Z&timeout=1m30s&pages=1,2&pages=3&flags=true,false&color=green", nil)
+
+	id, fieldError := ExtractInt64(r, "id", true)
+	if fieldError != nil || id != 12345678901 {
+		t.Errorf("Unexpected %d, %v", id, fieldError)
+	}
+	limit, _ := ExtractUint(r, "limit", true)
+	if limit != 7 {
+		t.Errorf("Unexpected %d", limit)
+	}
+	ratio, _ := ExtractFloat(r, "ratio", true)
+	if ratio != 0.5 {
+		t.Errorf("Unexpected %f", ratio)
+	}
+	since, _ := ExtractTime(r, "since", true)
+	if !since.Equal(time.Date(2016, time.July, 14, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected %v", since)
+	}
+	timeout, _ := ExtractDuration(r, "timeout", true)
+	if timeout != 90*time.Second {
+		t.Errorf("Unexpected %v", timeout)
+	}
+	pages, _ := ExtractNumberSlice(r, "pages", true)
+	if !reflect.DeepEqual(pages, []int{1, 2, 3}) {
+		t.Errorf("Unexpected %v", pages)
+	}
+	flags, _ := ExtractBoolSlice(r, "flags", true)
+	if !reflect.DeepEqual(flags, []bool{true, false}) {
+		t.Errorf("Unexpected %v", flags)
+	}
+	c, fieldError := ExtractEnum[color](r, "color", true)
+	if fieldError != nil || c != 2 {
+		t.Errorf("Unexpected %d, %v", c, fieldError)
+	}
+}
+
+func TestExtractInvalidTypedParameters(t *testing.T) {
+	r := httptest.NewRequest("GET", "/search?limit=-1&since=yesterday&pages=1,two&color=blue", nil)
+
+	_, fieldError := ExtractUint(r, "limit", false)
+	if fieldError == nil || fieldError.SubCode != SubCodeInvalid {
+		t.Errorf("Expected invalid limit, got %v", fieldError)
+	}
+	_, fieldError = ExtractTime(r, "since", false)
+	if fieldError == nil || fieldError.SubCode != SubCodeInvalid {
+		t.Errorf("Expected invalid since, got %v", fieldError)
+	}
+	_, fieldError = ExtractNumberSlice(r, "pages", false)
+	if fieldError == nil || fieldError.SubCode != SubCodeInvalid {
+		t.Errorf("Expected invalid pages, got %v", fieldError)
+	}
+	_, fieldError = ExtractEnum[color](r, "color", false)
+	if fieldError == nil || fieldError.SubCode != SubCodeInvalid {
+		t.Errorf("Expected invalid color, got %v", fieldError)
+	}
+	_, fieldError = ExtractBoolSlice(r, "flags", true)
+	if fieldError == nil || fieldError.SubCode != SubCodeMissing {
+		t.Errorf("Expected missing flags, got %v", fieldError)
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	r := httptest.NewRequest("GET", "/search?limit=5", nil)
+	SetDefault(r, "limit", "10")
+	SetDefault(r, "pages", "1,2")
+
+	limit, _ := ExtractNumber(r, "limit", true)
+	if limit != 5 {
+		t.Errorf("Unexpected %d", limit)
+	}
+	pages, _ := ExtractNumberSlice(r, "pages", true)
+	if !reflect.DeepEqual(pages, []int{1, 2}) {
+		t.Errorf("Unexpected %v", pages)
+	}
+}
+
+func TestExtractFromHeaderAndCookie(t *testing.T) {
+	r := httptest.NewRequest("GET", "/search?tenant=query", nil)
+	r.Header.Set("X-Tenant-ID", "acme")
+	r.AddCookie(&http.Cookie{Name: "session", Value: "42"})
+
+	tenant, fieldError := ExtractString(HeaderParams(r, "X-Tenant-ID"), "X-Tenant-ID", true)
+	if fieldError != nil || tenant != "acme" {
+		t.Errorf("Unexpected %s, %v", tenant, fieldError)
+	}
+	session, fieldError := ExtractNumber(CookieParams(r, "session"), "session", true)
+	if fieldError != nil || session != 42 {
+		t.Errorf("Unexpected %d, %v", session, fieldError)
+	}
+	_, fieldError = ExtractString(HeaderParams(r, "X-Missing"), "X-Missing", true)
+	if fieldError == nil || fieldError.SubCode != SubCodeMissing || fieldError.Field != "X-Missing" {
+		t.Errorf("Expected missing header, got %v", fieldError)
+	}
+}
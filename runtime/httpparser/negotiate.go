@@ -0,0 +1,78 @@
+package httpparser
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// mediaRange is a media-range of an Accept-header, like "text/*;q=0.8"
+type mediaRange struct {
+	mainType string
+	subType  string
+	quality  float64
+}
+
+func parseAccept(header string) []mediaRange {
+	ranges := []mediaRange{}
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		mainType, subType, ok := strings.Cut(strings.ToLower(strings.TrimSpace(params[0])), "/")
+		if !ok {
+			continue
+		}
+		mr := mediaRange{mainType: mainType, subType: subType, quality: 1}
+		for _, param := range params[1:] {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if name == "q" {
+				if quality, err := strconv.ParseFloat(value, 64); err == nil {
+					mr.quality = quality
+				}
+			}
+		}
+		ranges = append(ranges, mr)
+	}
+	return ranges
+}
+
+// quality returns the quality of the most specific media-range that matches the media-type
+func quality(ranges []mediaRange, mediaType string) float64 {
+	mainType, subType, _ := strings.Cut(mediaType, "/")
+	result, specificity := 0.0, -1
+	for _, mr := range ranges {
+		s := -1
+		switch {
+		case mr.mainType == mainType && mr.subType == subType:
+			s = 2
+		case mr.mainType == mainType && mr.subType == "*":
+			s = 1
+		case mr.mainType == "*" && mr.subType == "*":
+			s = 0
+		}
+		if s > specificity {
+			result, specificity = mr.quality, s
+		}
+	}
+	return result
+}
+
+// NegotiateFormat returns the offered media-type that the Accept-header of the request prefers; on a tie the one
+// that is offered first. Without Accept-header the first one is returned. It returns false when none of them is
+// acceptable.
+func NegotiateFormat(r *http.Request, offered ...string) (string, bool) {
+	if len(offered) == 0 {
+		return "", false
+	}
+	accept := strings.Join(r.Header.Values("Accept"), ",")
+	if strings.TrimSpace(accept) == "" {
+		return offered[0], true
+	}
+	ranges := parseAccept(accept)
+	best, bestQuality := "", 0.0
+	for _, mediaType := range offered {
+		if q := quality(ranges, mediaType); q > bestQuality {
+			best, bestQuality = mediaType, q
+		}
+	}
+	return best, bestQuality > 0
+}
@@ -0,0 +1,31 @@
+package httpparser
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	offered := []string{"application/json", "application/xml", "text/csv"}
+	for accept, expected := range map[string]string{
+		"":                                  "application/json",
+		"*/*":                               "application/json",
+		"text/csv":                          "text/csv",
+		"application/xml, application/json": "application/json",
+		"application/json;q=0.5, text/*":    "text/csv",
+		"text/*;q=0.9, */*;q=0.1":           "text/csv",
+		"application/*;q=0.2, text/csv;q=0": "application/json",
+		"Application/XML;q=1, */*;q=0.5":    "application/xml",
+		"text/html, application/xhtml+xml":  "",
+		"text/csv;q=0, application/*;q=0.0": "",
+	} {
+		r := httptest.NewRequest("GET", "/export", nil)
+		if accept != "" {
+			r.Header.Set("Accept", accept)
+		}
+		format, ok := NegotiateFormat(r, offered...)
+		if format != expected || ok != (expected != "") {
+			t.Errorf("Accept %q: expected %q, got %q, %v", accept, expected, format, ok)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package httpparser
+
+import (
+	"net/mail"
+
+	"github.com/f0rt/golangAnnotations/runtime/errorh"
+)
+
+// MissingField reports a mandatory field of the request-body without value
+func MissingField(name string) errorh.FieldError {
+	return errorh.FieldError{SubCode: SubCodeMissing, Field: name, Msg: "Missing value for mandatory field " + name}
+}
+
+// InvalidField reports a field of the request-body whose value violates a rule, like "must be at least 1"
+func InvalidField(name string, rule string) errorh.FieldError {
+	return errorh.FieldError{SubCode: SubCodeInvalid, Field: name, Msg: "Invalid value for field " + name + ": " + rule}
+}
+
+// IsEmail tells if value is a bare e-mail address, like john@example.com
+func IsEmail(value string) bool {
+	address, err := mail.ParseAddress(value)
+	return err == nil && address.Address == value
+}
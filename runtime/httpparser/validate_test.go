@@ -0,0 +1,30 @@
+package httpparser
+
+import "testing"
+
+func TestIsEmail(t *testing.T) {
+	for value, expected := range map[string]bool{
+		"john@example.com":               true,
+		"john.doe+tours@mail.example.nl": true,
+		"":                               false,
+		"john":                           false,
+		"john@":                          false,
+		"John <john@example.com>":        false,
+		" john@example.com":              false,
+	} {
+		if IsEmail(value) != expected {
+			t.Errorf("IsEmail(%q): expected %v", value, expected)
+		}
+	}
+}
+
+func TestFieldErrors(t *testing.T) {
+	missing := MissingField("name")
+	if missing.SubCode != SubCodeMissing || missing.Field != "name" || missing.Msg != "Missing value for mandatory field name" {
+		t.Errorf("Unexpected field-error %+v", missing)
+	}
+	invalid := InvalidField("age", "must be at least 18")
+	if invalid.SubCode != SubCodeInvalid || invalid.Field != "age" || invalid.Msg != "Invalid value for field age: must be at least 18" {
+		t.Errorf("Unexpected field-error %+v", invalid)
+	}
+}
@@ -0,0 +1,24 @@
+// Package idempotency prevents that an event is applied to an aggregate more than once
+package idempotency
+
+// Checker is implemented by aggregates: it remembers the uuids of the events that were applied
+type Checker interface {
+	IsEventProcessed(uuid string) bool
+	MarkEventProcessed(uuid string)
+}
+
+// Processed implements Checker; it can be embedded in an aggregate
+type Processed struct {
+	uuids map[string]bool
+}
+
+func (p *Processed) IsEventProcessed(uuid string) bool {
+	return p.uuids[uuid]
+}
+
+func (p *Processed) MarkEventProcessed(uuid string) {
+	if p.uuids == nil {
+		p.uuids = map[string]bool{}
+	}
+	p.uuids[uuid] = true
+}
@@ -0,0 +1,14 @@
+package idempotency
+
+import "testing"
+
+func TestProcessed(t *testing.T) {
+	var checker Checker = &Processed{}
+	if checker.IsEventProcessed("1") {
+		t.Errorf("Expected nothing processed")
+	}
+	checker.MarkEventProcessed("1")
+	if !checker.IsEventProcessed("1") || checker.IsEventProcessed("2") {
+		t.Errorf("Expected only 1 processed")
+	}
+}
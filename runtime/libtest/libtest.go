@@ -0,0 +1,178 @@
+// Package libtest records the http-requests and -responses of integration tests, so they double as documentation
+package libtest
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// TestLogSuffix is appended to the package-name to find the package that serves the recorded test-cases
+var TestLogSuffix = "TestLog"
+
+// HTTPTestCase records a single call of an operation
+type HTTPTestCase struct {
+	Name           string
+	Description    string
+	OperationName  string
+	allowedEvents  []string
+	eventsBefore   []string
+	ProducedEvents []string
+
+	Method         string
+	URL            string
+	RequestHeader  http.Header
+	RequestBody    []byte
+	StatusCode     int
+	ResponseHeader http.Header
+	ResponseBody   []byte
+}
+
+func NewHTTPTestCase(name string, description string) *HTTPTestCase {
+	return &HTTPTestCase{
+		Name:        name,
+		Description: description,
+	}
+}
+
+func (tc *HTTPTestCase) ForOperationName(name string) *HTTPTestCase {
+	tc.OperationName = name
+	return tc
+}
+
+// WithAllowedPostConditions lists the events ("<aggregate>.<event>") that the operation may produce
+func (tc *HTTPTestCase) WithAllowedPostConditions(events []string) *HTTPTestCase {
+	tc.allowedEvents = events
+	return tc
+}
+
+// WithPreConditions records the events that existed before the operation was called
+func (tc *HTTPTestCase) WithPreConditions(events []string) *HTTPTestCase {
+	tc.eventsBefore = events
+	return tc
+}
+
+// WithPostConditions records the events that the operation produced; an error is returned when they are not allowed
+func (tc *HTTPTestCase) WithPostConditions(events []string) (*HTTPTestCase, error) {
+	if len(events) >= len(tc.eventsBefore) {
+		tc.ProducedEvents = events[len(tc.eventsBefore):]
+	}
+	for _, produced := range tc.ProducedEvents {
+		if !contains(tc.allowedEvents, produced) {
+			return tc, fmt.Errorf("Operation %s produced event %s, only %v are allowed", tc.OperationName, produced, tc.allowedEvents)
+		}
+	}
+	return tc, nil
+}
+
+func (tc *HTTPTestCase) WithRequest(method string, url string, header http.Header, body []byte) *HTTPTestCase {
+	tc.Method = method
+	tc.URL = url
+	tc.RequestHeader = header
+	tc.RequestBody = body
+	return tc
+}
+
+func (tc *HTTPTestCase) WithResponse(statusCode int, header http.Header, body []byte) *HTTPTestCase {
+	tc.StatusCode = statusCode
+	tc.ResponseHeader = header
+	tc.ResponseBody = body
+	return tc
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// HTTPTestSuite collects the test-cases of a package
+type HTTPTestSuite struct {
+	sync.Mutex
+	PackageName string
+	TestCases   []*HTTPTestCase
+}
+
+func NewHTTPTestSuite(packageName string) *HTTPTestSuite {
+	return &HTTPTestSuite{
+		PackageName: packageName,
+		TestCases:   []*HTTPTestCase{},
+	}
+}
+
+func (s *HTTPTestSuite) Add(tc *HTTPTestCase) {
+	s.Lock()
+	defer s.Unlock()
+	s.TestCases = append(s.TestCases, tc)
+}
+
+// Markdown describes all test-cases, grouped by operation
+func (s *HTTPTestSuite) Markdown() string {
+	s.Lock()
+	defer s.Unlock()
+
+	testCases := append([]*HTTPTestCase{}, s.TestCases...)
+	sort.SliceStable(testCases, func(i, j int) bool {
+		return testCases[i].OperationName < testCases[j].OperationName
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", s.PackageName)
+	operationName := ""
+	for _, tc := range testCases {
+		if tc.OperationName != operationName {
+			operationName = tc.OperationName
+			fmt.Fprintf(&b, "\n## %s\n", operationName)
+		}
+		fmt.Fprintf(&b, "\n### %s\n", strings.TrimSpace(tc.Name+" "+tc.Description))
+		fmt.Fprintf(&b, "\nRequest:\n\n    %s %s\n", tc.Method, tc.URL)
+		writeHeader(&b, tc.RequestHeader)
+		writeBody(&b, tc.RequestBody)
+		fmt.Fprintf(&b, "\nResponse:\n\n    %d %s\n", tc.StatusCode, http.StatusText(tc.StatusCode))
+		writeHeader(&b, tc.ResponseHeader)
+		writeBody(&b, tc.ResponseBody)
+		if len(tc.ProducedEvents) > 0 {
+			fmt.Fprintf(&b, "\nProduced events: %s\n", strings.Join(tc.ProducedEvents, ", "))
+		}
+	}
+	return b.String()
+}
+
+func writeHeader(b *strings.Builder, header http.Header) {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(b, "    %s: %s\n", key, strings.Join(header[key], ", "))
+	}
+}
+
+func writeBody(b *strings.Builder, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n    %s\n", strings.ReplaceAll(strings.TrimSpace(string(body)), "\n", "\n    "))
+}
+
+// WriteToMarkdownGoVarFile makes the test-log package of the tested package serve the markdown of the test-cases.
+// Nothing is written when that package does not exist.
+func (s *HTTPTestSuite) WriteToMarkdownGoVarFile() error {
+	testLogPackage := s.PackageName + TestLogSuffix
+	info, err := os.Stat(testLogPackage)
+	if err != nil || !info.IsDir() {
+		return nil
+	}
+	src := fmt.Sprintf("// Code generated by libtest. DO NOT EDIT.\n\npackage %s\n\nfunc init() {\n\ttestResults = %s\n}\n",
+		testLogPackage, strconv.Quote(s.Markdown()))
+	return os.WriteFile(filepath.Join(testLogPackage, "gen_testResults.go"), []byte(src), 0644)
+}
@@ -0,0 +1,38 @@
+package libtest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPostConditions(t *testing.T) {
+	tc := NewHTTPTestCase("create", "creates a tour").
+		ForOperationName("createTour").
+		WithAllowedPostConditions([]string{"Tour.TourCreated"}).
+		WithPreConditions([]string{"Tour.TourCreated"})
+
+	_, err := tc.WithPostConditions([]string{"Tour.TourCreated", "Tour.TourCreated"})
+	if err != nil {
+		t.Errorf("Unexpected error %s", err)
+	}
+	_, err = tc.WithPostConditions([]string{"Tour.TourCreated", "Tour.CyclistCreated"})
+	if err == nil {
+		t.Errorf("Expected error for event that is not allowed")
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	suite := NewHTTPTestSuite("myrest")
+	suite.Add(NewHTTPTestCase("get", "").
+		ForOperationName("getTour").
+		WithRequest("GET", "/api/tour/2016", http.Header{"Accept": {"application/json"}}, nil).
+		WithResponse(http.StatusOK, http.Header{}, []byte(`{"year":2016}`)))
+
+	markdown := suite.Markdown()
+	for _, expected := range []string{"# myrest", "## getTour", "    GET /api/tour/2016", "    Accept: application/json", "    200 OK", `    {"year":2016}`} {
+		if !strings.Contains(markdown, expected) {
+			t.Errorf("Expected %q in:\n%s", expected, markdown)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Package mydate provides a calendar date without time and timezone
+package mydate
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Layout is the format of a MyDate in urls and json
+const Layout = "2006-01-02"
+
+// MyDate is a calendar date; its zero value is the empty date
+type MyDate struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+func New(year int, month time.Month, day int) MyDate {
+	return MyDate{Year: year, Month: month, Day: day}
+}
+
+// Parse reads a date in the format of Layout; an empty string yields the empty date
+func Parse(value string) (MyDate, error) {
+	if value == "" {
+		return MyDate{}, nil
+	}
+	t, err := time.Parse(Layout, value)
+	if err != nil {
+		return MyDate{}, fmt.Errorf("Invalid date %s: expected format %s", value, Layout)
+	}
+	return New(t.Date()), nil
+}
+
+func (d MyDate) IsZero() bool {
+	return d == MyDate{}
+}
+
+func (d MyDate) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+func (d MyDate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *MyDate) UnmarshalJSON(data []byte) error {
+	var value string
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return err
+	}
+	*d, err = Parse(value)
+	return err
+}
@@ -0,0 +1,35 @@
+package mydate
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	d, err := Parse("2016-07-14")
+	if err != nil || d != New(2016, time.July, 14) {
+		t.Errorf("Unexpected %v, %v", d, err)
+	}
+	d, err = Parse("")
+	if err != nil || !d.IsZero() {
+		t.Errorf("Expected empty date, got %v, %v", d, err)
+	}
+	_, err = Parse("14-07-2016")
+	if err == nil {
+		t.Errorf("Expected error")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	blob, err := json.Marshal(struct{ Day MyDate }{New(2016, time.July, 4)})
+	if err != nil || string(blob) != `{"Day":"2016-07-04"}` {
+		t.Errorf("Unexpected %s, %v", blob, err)
+	}
+
+	var s struct{ Day MyDate }
+	err = json.Unmarshal(blob, &s)
+	if err != nil || s.Day != New(2016, time.July, 4) {
+		t.Errorf("Unexpected %v, %v", s.Day, err)
+	}
+}
@@ -0,0 +1,40 @@
+// Package myerrorhandling keeps track of the events that subscribers fail to handle
+package myerrorhandling
+
+import (
+	"context"
+	"sync"
+
+	"github.com/f0rt/golangAnnotations/runtime/envelope"
+	"github.com/f0rt/golangAnnotations/runtime/mylog"
+	"github.com/f0rt/golangAnnotations/runtime/request"
+)
+
+var (
+	mutex   sync.Mutex
+	failing = map[string]string{}
+)
+
+// HandleEventError logs the failure and remembers the envelope as failing
+func HandleEventError(c context.Context, rc request.Context, topic string, envlp envelope.Envelope, msg string, err error) {
+	mylog.New().Error(c, rc, "%s on topic %s: %s", msg, topic, err)
+	mutex.Lock()
+	defer mutex.Unlock()
+	failing[envlp.UUID] = msg
+}
+
+// HandleEventClearError is called when a retry succeeded
+func HandleEventClearError(c context.Context, rc request.Context, topic string, envlp envelope.Envelope, msg string) {
+	mylog.New().Info(c, rc, "%s on topic %s", msg, topic)
+	mutex.Lock()
+	defer mutex.Unlock()
+	delete(failing, envlp.UUID)
+}
+
+// IsFailing tells whether handling the envelope failed and did not succeed since
+func IsFailing(envelopeUUID string) bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+	_, ok := failing[envelopeUUID]
+	return ok
+}
@@ -0,0 +1,24 @@
+package myerrorhandling
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/f0rt/golangAnnotations/runtime/envelope"
+	"github.com/f0rt/golangAnnotations/runtime/request"
+)
+
+func TestFailingEvents(t *testing.T) {
+	c, rc := context.Background(), request.NewEmptyContext()
+	envlp := envelope.Envelope{UUID: "1"}
+
+	HandleEventError(c, rc, "Tour", envlp, "Failed", fmt.Errorf("error"))
+	if !IsFailing("1") {
+		t.Errorf("Expected failing event")
+	}
+	HandleEventClearError(c, rc, "Tour", envlp, "Retry succeeded")
+	if IsFailing("1") {
+		t.Errorf("Expected error to be cleared")
+	}
+}
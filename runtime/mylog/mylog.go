@@ -0,0 +1,65 @@
+// Package mylog logs on behalf of a request
+package mylog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/f0rt/golangAnnotations/runtime/request"
+)
+
+// Logger prefixes every message with its level and the session of the request
+type Logger interface {
+	Debug(c context.Context, rc request.Context, format string, args ...interface{})
+	Info(c context.Context, rc request.Context, format string, args ...interface{})
+	Warning(c context.Context, rc request.Context, format string, args ...interface{})
+	Error(c context.Context, rc request.Context, format string, args ...interface{})
+}
+
+var (
+	mutex  sync.RWMutex
+	output = log.New(os.Stderr, "", log.LstdFlags)
+)
+
+// SetOutput redirects all log-messages, for example to verify them in a test
+func SetOutput(w io.Writer) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	output = log.New(w, "", log.LstdFlags)
+}
+
+type logger struct{}
+
+func New() Logger {
+	return logger{}
+}
+
+func (l logger) Debug(c context.Context, rc request.Context, format string, args ...interface{}) {
+	l.log("DEBUG", rc, format, args...)
+}
+
+func (l logger) Info(c context.Context, rc request.Context, format string, args ...interface{}) {
+	l.log("INFO", rc, format, args...)
+}
+
+func (l logger) Warning(c context.Context, rc request.Context, format string, args ...interface{}) {
+	l.log("WARNING", rc, format, args...)
+}
+
+func (l logger) Error(c context.Context, rc request.Context, format string, args ...interface{}) {
+	l.log("ERROR", rc, format, args...)
+}
+
+func (l logger) log(level string, rc request.Context, format string, args ...interface{}) {
+	session := ""
+	if rc != nil && rc.GetSessionUID() != "" {
+		session = fmt.Sprintf("[%s] ", rc.GetSessionUID())
+	}
+	mutex.RLock()
+	defer mutex.RUnlock()
+	output.Printf("%s: %s%s", level, session, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,22 @@
+package mylog
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/f0rt/golangAnnotations/runtime/request"
+)
+
+func TestLog(t *testing.T) {
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	defer SetOutput(os.Stderr)
+
+	New().Warning(context.Background(), request.New(request.SessionUID("session")), "Error %d", 42)
+	if !strings.HasSuffix(buf.String(), "WARNING: [session] Error 42\n") {
+		t.Errorf("Unexpected log %q", buf.String())
+	}
+}
@@ -0,0 +1,91 @@
+// Package myqueue keeps the tasks of event services in memory until they are executed
+package myqueue
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/f0rt/golangAnnotations/runtime/queue"
+	"github.com/f0rt/golangAnnotations/runtime/request"
+)
+
+// ProcessType selects the queue of a task
+type ProcessType string
+
+const (
+	ProcessTypeDefault ProcessType = "default"
+)
+
+// HeaderTaskRetryCount tells the handler of a task how often it was tried before
+const HeaderTaskRetryCount = "X-AppEngine-TaskRetryCount"
+
+type pendingTask struct {
+	processType ProcessType
+	task        queue.Task
+}
+
+var (
+	mutex   sync.Mutex
+	pending = []pendingTask{}
+)
+
+// AddTask enqueues a task
+func AddTask(c context.Context, rc request.Context, processType ProcessType, task queue.Task) error {
+	if task.URL == "" {
+		return fmt.Errorf("Task without url")
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	pending = append(pending, pendingTask{processType: processType, task: task})
+	return nil
+}
+
+// Tasks returns the pending tasks of a queue
+func Tasks(processType ProcessType) []queue.Task {
+	mutex.Lock()
+	defer mutex.Unlock()
+	tasks := []queue.Task{}
+	for _, p := range pending {
+		if p.processType == processType {
+			tasks = append(tasks, p.task)
+		}
+	}
+	return tasks
+}
+
+// Reset removes all pending tasks
+func Reset() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	pending = []pendingTask{}
+}
+
+// Execute runs all pending tasks against the handler, ignoring their delay, until no tasks are left. The errors of
+// the tasks that fail are returned.
+func Execute(handler http.Handler) error {
+	errs := []error{}
+	for {
+		mutex.Lock()
+		tasks := pending
+		pending = []pendingTask{}
+		mutex.Unlock()
+		if len(tasks) == 0 {
+			return errors.Join(errs...)
+		}
+
+		for _, p := range tasks {
+			r := httptest.NewRequest(p.task.Method, p.task.URL, bytes.NewReader(p.task.Payload))
+			r.Header.Set(HeaderTaskRetryCount, "0")
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code >= http.StatusBadRequest {
+				errs = append(errs, fmt.Errorf("Task %s %s failed with status %d: %s", p.task.Method, p.task.URL, w.Code, w.Body.String()))
+			}
+		}
+	}
+}
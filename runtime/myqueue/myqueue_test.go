@@ -0,0 +1,40 @@
+package myqueue
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/f0rt/golangAnnotations/runtime/queue"
+	"github.com/f0rt/golangAnnotations/runtime/request"
+)
+
+func TestExecute(t *testing.T) {
+	defer Reset()
+	c, rc := context.Background(), request.NewEmptyContext()
+
+	AddTask(c, rc, ProcessTypeDefault, queue.Task{Method: "POST", URL: "/tasks/ok"})
+	AddTask(c, rc, ProcessTypeDefault, queue.Task{Method: "POST", URL: "/tasks/fail"})
+	if len(Tasks(ProcessTypeDefault)) != 2 {
+		t.Fatalf("Expected 2 tasks")
+	}
+
+	executed := []string{}
+	err := Execute(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		executed = append(executed, r.URL.Path)
+		if r.URL.Path == "/tasks/ok" {
+			// tasks that are added while executing are executed as well
+			AddTask(c, rc, ProcessTypeDefault, queue.Task{Method: "POST", URL: "/tasks/next"})
+			return
+		}
+		if r.URL.Path == "/tasks/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	if err == nil {
+		t.Errorf("Expected error of failing task")
+	}
+	if len(executed) != 3 || len(Tasks(ProcessTypeDefault)) != 0 {
+		t.Errorf("Unexpected %v", executed)
+	}
+}
@@ -0,0 +1,56 @@
+// Package mytime provides the current time in a way that can be mocked in tests
+package mytime
+
+import (
+	"sync"
+	"time"
+
+	_ "time/tzdata" // DutchLocation does not depend on the timezone-database of the host
+)
+
+// DutchLocation is the timezone in which event timestamps are presented by default
+var DutchLocation = mustLoadLocation("Europe/Amsterdam")
+
+// MockNow is the moment that Now returns after SetMockNow
+var MockNow = time.Date(2016, time.February, 27, 0, 0, 0, 0, DutchLocation)
+
+var (
+	mutex sync.RWMutex
+	now   = time.Now
+)
+
+// Now returns the current time, or the mocked time
+func Now() time.Time {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return now()
+}
+
+// SetMockNow makes Now return MockNow
+func SetMockNow() {
+	SetNow(MockNow)
+}
+
+// SetNow makes Now return the given moment
+func SetNow(moment time.Time) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	now = func() time.Time {
+		return moment
+	}
+}
+
+// SetDefaultNow makes Now return the real current time again
+func SetDefaultNow() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	now = time.Now
+}
+
+func mustLoadLocation(name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return location
+}
@@ -0,0 +1,20 @@
+package mytime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMockNow(t *testing.T) {
+	defer SetDefaultNow()
+
+	SetMockNow()
+	if got := Now().Format(time.RFC3339); got != "2016-02-27T00:00:00+01:00" {
+		t.Errorf("Expected mocked time, got %s", got)
+	}
+
+	SetDefaultNow()
+	if Now().Year() == 2016 {
+		t.Errorf("Expected real time, got %s", Now())
+	}
+}
@@ -0,0 +1,49 @@
+// Package myuuid creates unique identifiers in a way that can be mocked in tests
+package myuuid
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sync"
+)
+
+var (
+	mutex sync.Mutex
+	mocks = map[string]string{}
+)
+
+// NewV1 returns a new random identifier in uuid-format, or the identifier that is mocked for the given name
+func NewV1(name string) (string, error) {
+	mutex.Lock()
+	mocked, ok := mocks[name]
+	mutex.Unlock()
+	if ok {
+		return mocked, nil
+	}
+	return newRandom()
+}
+
+// SetMockV1 makes NewV1 return uid for the given name
+func SetMockV1(name string, uid string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	mocks[name] = uid
+}
+
+// SetDefaults removes all mocks
+func SetDefaults() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	mocks = map[string]string{}
+}
+
+func newRandom() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("Error creating uuid: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant RFC 4122
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
@@ -0,0 +1,37 @@
+package myuuid
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestNewV1(t *testing.T) {
+	uid1, err := NewV1("Tour")
+	if err != nil {
+		t.Fatal(err)
+	}
+	uid2, _ := NewV1("Tour")
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(uid1) {
+		t.Errorf("Invalid uuid %s", uid1)
+	}
+	if uid1 == uid2 {
+		t.Errorf("Expected unique uuids, got %s twice", uid1)
+	}
+}
+
+func TestSetMockV1(t *testing.T) {
+	defer SetDefaults()
+
+	SetMockV1("Tour", "1234321")
+	if uid, _ := NewV1("Tour"); uid != "1234321" {
+		t.Errorf("Expected mocked uid, got %s", uid)
+	}
+	if uid, _ := NewV1("Gambler"); uid == "1234321" {
+		t.Errorf("Expected mock for Tour only")
+	}
+
+	SetDefaults()
+	if uid, _ := NewV1("Tour"); uid == "1234321" {
+		t.Errorf("Expected mock to be removed")
+	}
+}
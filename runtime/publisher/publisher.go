@@ -0,0 +1,15 @@
+// Package publisher publishes transient events: they are not stored
+package publisher
+
+import (
+	"context"
+
+	"github.com/f0rt/golangAnnotations/runtime/bus"
+	"github.com/f0rt/golangAnnotations/runtime/envelope"
+	"github.com/f0rt/golangAnnotations/runtime/request"
+)
+
+// PublishEnvelope delivers the envelope to the subscribers of its aggregate right away
+func PublishEnvelope(c context.Context, rc request.Context, envlp *envelope.Envelope) error {
+	return bus.New().Publish(c, rc, *envlp)
+}
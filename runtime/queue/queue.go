@@ -0,0 +1,15 @@
+// Package queue describes tasks that are executed in the background
+package queue
+
+import "time"
+
+// Task is an http-request that is executed later on
+type Task struct {
+	Method  string
+	URL     string
+	Payload []byte
+
+	// Delay and ETA postpone the execution of the task
+	Delay time.Duration
+	ETA   time.Time
+}
@@ -0,0 +1,180 @@
+// Package request provides the context of a single request: who made it and which events it produced
+package request
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/f0rt/golangAnnotations/runtime/envelope"
+)
+
+// Headers from which the request-context is read
+const (
+	HeaderSessionUID = "X-Session-Uid"
+	HeaderRequestUID = "X-Request-Uid"
+	HeaderAuthUser   = "X-Auth-User"
+)
+
+// Context is passed along with the context.Context to business logic, event handlers and stores
+type Context interface {
+	GetSessionUID() string
+	GetRequestUID() string
+	GetAuthUser() string
+	SetAuthUser(uid string)
+	GetTaskRetryCount() int
+	IsTransactional() bool
+
+	// GetEnvelopes returns the envelopes that were stored while handling the request: they are published afterwards
+	GetEnvelopes() []envelope.Envelope
+	AddEnvelope(envlp envelope.Envelope)
+
+	Set(options ...Option)
+}
+
+// Option changes a Context
+type Option func(rc *requestContext)
+
+func SessionUID(uid string) Option {
+	return func(rc *requestContext) {
+		rc.sessionUID = uid
+	}
+}
+
+func RequestUID(uid string) Option {
+	return func(rc *requestContext) {
+		rc.requestUID = uid
+	}
+}
+
+func TaskRetryCount(count int) Option {
+	return func(rc *requestContext) {
+		rc.taskRetryCount = count
+	}
+}
+
+func Transactional(transactional bool) Option {
+	return func(rc *requestContext) {
+		rc.transactional = transactional
+	}
+}
+
+// ClearEnvelopes forgets the stored envelopes, for example when the transaction that stored them failed
+func ClearEnvelopes() Option {
+	return func(rc *requestContext) {
+		rc.envelopes = []envelope.Envelope{}
+	}
+}
+
+type requestContext struct {
+	sync.Mutex
+	sessionUID     string
+	requestUID     string
+	authUser       string
+	taskRetryCount int
+	transactional  bool
+	envelopes      []envelope.Envelope
+}
+
+func New(options ...Option) Context {
+	rc := &requestContext{
+		envelopes: []envelope.Envelope{},
+	}
+	rc.Set(options...)
+	return rc
+}
+
+// NewEmptyContext is used where there is no request, for example in tests
+func NewEmptyContext() Context {
+	return New()
+}
+
+// NewMinimalContext reads the session and request uids from the http-request
+func NewMinimalContext(c context.Context, r *http.Request) Context {
+	return New(
+		SessionUID(r.Header.Get(HeaderSessionUID)),
+		RequestUID(r.Header.Get(HeaderRequestUID)),
+	)
+}
+
+// AuthUserFunc returns the authenticated user of the http-request, or "" when the request is not authenticated
+type AuthUserFunc func(c context.Context, r *http.Request) string
+
+// authUserFunc trusts nothing by default, so requests have no authenticated user until the application sets one
+var authUserFunc AuthUserFunc = func(c context.Context, r *http.Request) string {
+	return ""
+}
+
+// SetAuthUserFunc sets how NewContext determines the authenticated user; call it at startup, before serving requests.
+// Any client can send a header like HeaderAuthUser: only read it when a proxy in front authenticates and overwrites it.
+func SetAuthUserFunc(f AuthUserFunc) {
+	authUserFunc = f
+}
+
+// NewContext determines the authenticated user of the http-request as well, see SetAuthUserFunc
+func NewContext(c context.Context, r *http.Request) Context {
+	rc := NewMinimalContext(c, r)
+	rc.SetAuthUser(authUserFunc(c, r))
+	return rc
+}
+
+// NewAdminContext is used for operations that are reserved for administrators
+func NewAdminContext(c context.Context, r *http.Request) Context {
+	return NewContext(c, r)
+}
+
+func (rc *requestContext) Set(options ...Option) {
+	rc.Lock()
+	defer rc.Unlock()
+	for _, option := range options {
+		option(rc)
+	}
+}
+
+func (rc *requestContext) GetSessionUID() string {
+	rc.Lock()
+	defer rc.Unlock()
+	return rc.sessionUID
+}
+
+func (rc *requestContext) GetRequestUID() string {
+	rc.Lock()
+	defer rc.Unlock()
+	return rc.requestUID
+}
+
+func (rc *requestContext) GetAuthUser() string {
+	rc.Lock()
+	defer rc.Unlock()
+	return rc.authUser
+}
+
+func (rc *requestContext) SetAuthUser(uid string) {
+	rc.Lock()
+	defer rc.Unlock()
+	rc.authUser = uid
+}
+
+func (rc *requestContext) GetTaskRetryCount() int {
+	rc.Lock()
+	defer rc.Unlock()
+	return rc.taskRetryCount
+}
+
+func (rc *requestContext) IsTransactional() bool {
+	rc.Lock()
+	defer rc.Unlock()
+	return rc.transactional
+}
+
+func (rc *requestContext) GetEnvelopes() []envelope.Envelope {
+	rc.Lock()
+	defer rc.Unlock()
+	return append([]envelope.Envelope{}, rc.envelopes...)
+}
+
+func (rc *requestContext) AddEnvelope(envlp envelope.Envelope) {
+	rc.Lock()
+	defer rc.Unlock()
+	rc.envelopes = append(rc.envelopes, envlp)
+}
@@ -0,0 +1,51 @@
+package request
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/f0rt/golangAnnotations/runtime/envelope"
+)
+
+func TestOptions(t *testing.T) {
+	rc := New(SessionUID("session"), RequestUID("request"), TaskRetryCount(2), Transactional(true))
+	if rc.GetSessionUID() != "session" || rc.GetRequestUID() != "request" || rc.GetTaskRetryCount() != 2 || !rc.IsTransactional() {
+		t.Errorf("Options not applied")
+	}
+}
+
+func TestEnvelopes(t *testing.T) {
+	rc := NewEmptyContext()
+	rc.AddEnvelope(envelope.Envelope{UUID: "1"})
+	rc.AddEnvelope(envelope.Envelope{UUID: "2"})
+	if len(rc.GetEnvelopes()) != 2 {
+		t.Errorf("Expected 2 envelopes, got %d", len(rc.GetEnvelopes()))
+	}
+	rc.Set(ClearEnvelopes())
+	if len(rc.GetEnvelopes()) != 0 {
+		t.Errorf("Expected envelopes to be cleared")
+	}
+}
+
+func TestNewContextFromHeaders(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set(HeaderSessionUID, "session")
+	r.Header.Set(HeaderAuthUser, "admin")
+
+	if rc := NewMinimalContext(context.Background(), r); rc.GetSessionUID() != "session" || rc.GetAuthUser() != "" {
+		t.Errorf("Unexpected minimal context")
+	}
+	if rc := NewContext(context.Background(), r); rc.GetAuthUser() != "" {
+		t.Errorf("Expected the header not to be trusted by default")
+	}
+
+	SetAuthUserFunc(func(c context.Context, r *http.Request) string {
+		return r.Header.Get(HeaderAuthUser)
+	})
+	defer SetAuthUserFunc(func(c context.Context, r *http.Request) string { return "" })
+	if rc := NewContext(context.Background(), r); rc.GetAuthUser() != "admin" {
+		t.Errorf("Expected auth user")
+	}
+}
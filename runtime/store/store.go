@@ -0,0 +1,41 @@
+// Package store stores the events that business logic produces, so they are published once the request succeeds
+package store
+
+import (
+	"context"
+
+	"github.com/f0rt/golangAnnotations/runtime/datastore"
+	"github.com/f0rt/golangAnnotations/runtime/envelope"
+	"github.com/f0rt/golangAnnotations/runtime/eventStore"
+	"github.com/f0rt/golangAnnotations/runtime/request"
+)
+
+// Wrapper is implemented by every generated event
+type Wrapper interface {
+	Wrap(rc request.Context) (*envelope.Envelope, error)
+}
+
+// Put stores the envelope in the event-store and registers it with the request for publication
+func Put(c context.Context, rc request.Context, tx *datastore.Transaction, envlp *envelope.Envelope) error {
+	err := eventStore.Mocked().Put(c, rc, tx, envlp)
+	if err != nil {
+		return err
+	}
+	tx.OnCommit(func() {
+		rc.AddEnvelope(*envlp)
+	})
+	return nil
+}
+
+// StoreEvent wraps and stores an event outside any transaction
+func StoreEvent(c context.Context, rc request.Context, evt Wrapper) (*envelope.Envelope, error) {
+	envlp, err := evt.Wrap(rc)
+	if err != nil {
+		return nil, err
+	}
+	err = Put(c, rc, nil, envlp)
+	if err != nil {
+		return nil, err
+	}
+	return envlp, nil
+}
@@ -0,0 +1,36 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/f0rt/golangAnnotations/runtime/envelope"
+	"github.com/f0rt/golangAnnotations/runtime/eventStore"
+	"github.com/f0rt/golangAnnotations/runtime/request"
+)
+
+type tourCreated struct{}
+
+func (tourCreated) Wrap(rc request.Context) (*envelope.Envelope, error) {
+	return &envelope.Envelope{UUID: "1", AggregateName: "Tour", AggregateUID: "2016", EventTypeName: "TourCreated"}, nil
+}
+
+func TestStoreEvent(t *testing.T) {
+	defer eventStore.Mocked().Reset()
+	c, rc := context.Background(), request.NewEmptyContext()
+
+	envlp, err := StoreEvent(c, rc, tourCreated{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if envlp.SequenceNumber != 1 {
+		t.Errorf("Expected sequence-number to be set, got %d", envlp.SequenceNumber)
+	}
+	if len(rc.GetEnvelopes()) != 1 {
+		t.Errorf("Expected envelope to be registered for publication")
+	}
+	exists, _ := eventStore.Mocked().Exists(c, rc, "Tour", "2016")
+	if !exists {
+		t.Errorf("Expected envelope to be stored")
+	}
+}